@@ -0,0 +1,158 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"github.com/nsf/termbox-go"
+)
+
+// activityMap counts the state changes of every cell over the last window generations.
+type activityMap struct {
+	window   int
+	alive    map[Coord]bool
+	history  [][]Coord
+	next     int
+	recorded int
+	counts   map[Coord]int
+}
+
+// heatStops is the gradient from the rarely to the always changing cells.
+var heatStops = [][3]uint8{
+	{30, 30, 140},
+	{0, 140, 200},
+	{0, 180, 60},
+	{230, 200, 0},
+	{220, 40, 0},
+}
+
+var heatBasicColors = []termbox.Attribute{
+	termbox.ColorBlue,
+	termbox.ColorCyan,
+	termbox.ColorGreen,
+	termbox.ColorYellow,
+	termbox.ColorRed,
+}
+
+func newActivityMap(u Universe, window int) *activityMap {
+	a := &activityMap{
+		window:  max(window, 1),
+		alive:   make(map[Coord]bool, u.AliveCount()),
+		history: make([][]Coord, max(window, 1)),
+		counts:  make(map[Coord]int),
+	}
+	u.ForEachAlive(func(cell Coord, age int) {
+		a.alive[cell] = true
+	})
+	return a
+}
+
+// record adds the cells changed since the previous generation and forgets
+// the changes that fell out of the window.
+func (a *activityMap) record(u Universe) {
+	alive := make(map[Coord]bool, u.AliveCount())
+	var changed []Coord
+	u.ForEachAlive(func(cell Coord, age int) {
+		alive[cell] = true
+		if !a.alive[cell] {
+			changed = append(changed, cell)
+		}
+	})
+	for cell := range a.alive {
+		if !alive[cell] {
+			changed = append(changed, cell)
+		}
+	}
+	a.alive = alive
+
+	for _, cell := range a.history[a.next] {
+		if a.counts[cell]--; a.counts[cell] == 0 {
+			delete(a.counts, cell)
+		}
+	}
+	for _, cell := range changed {
+		a.counts[cell]++
+	}
+	a.history[a.next] = changed
+	a.next = (a.next + 1) % a.window
+	a.recorded = min(a.recorded+1, a.window)
+}
+
+// level returns the share of the recorded generations the most active cell
+// of the block changed in, 0 for a block without changes.
+func (a *activityMap) level(x int, y int, size int) float64 {
+	if a.recorded == 0 {
+		return 0
+	}
+	count := 0
+	for i := x; i < x+size; i++ {
+		for j := y; j < y+size; j++ {
+			count = max(count, a.counts[Coord{i, j}])
+		}
+	}
+	return float64(count) / float64(a.recorded)
+}
+
+// heatColor returns the gradient colour of the level for the output mode.
+func heatColor(level float64, mode termbox.OutputMode) termbox.Attribute {
+	level = min(max(level, 0), 1)
+
+	switch mode {
+	case termbox.OutputRGB, termbox.Output256:
+		position := level * float64(len(heatStops)-1)
+		i := min(int(position), len(heatStops)-2)
+		t := position - float64(i)
+		var rgb [3]uint8
+		for c := range rgb {
+			rgb[c] = uint8(float64(heatStops[i][c])*(1-t) + float64(heatStops[i+1][c])*t)
+		}
+		if mode == termbox.OutputRGB {
+			return termbox.RGBToAttribute(rgb[0], rgb[1], rgb[2])
+		}
+		return colorCubeAttribute(rgb)
+	default:
+		return heatBasicColors[min(int(level*float64(len(heatBasicColors))), len(heatBasicColors)-1)]
+	}
+}
+
+// colorCubeAttribute returns the nearest colour of the 6x6x6 cube of the 256 colour palette.
+func colorCubeAttribute(rgb [3]uint8) termbox.Attribute {
+	index := 16
+	for c, weight := range []int{36, 6, 1} {
+		index += weight * ((int(rgb[c])*5 + 127) / 255)
+	}
+	return termbox.Attribute(index + 1)
+}
+
+// ToggleActivity starts counting the cell changes over the window generations or stops it.
+func (game *Game) ToggleActivity(window int) {
+	if game.activity != nil {
+		game.activity = nil
+		game.SetStatus("Activity heat map off")
+		return
+	}
+	game.activity = newActivityMap(game.Universe, window)
+	game.SetStatus("Activity heat map on")
+}
+
+func (game *Game) recordActivity() {
+	if game.activity != nil {
+		game.activity.record(game.Universe)
+	}
+}
@@ -0,0 +1,156 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const checkpointExt = ".snapshot"
+
+// Autosaver writes session snapshots into a rotating set of checkpoint files
+// every N generations and/or every time interval.
+type Autosaver struct {
+	dir            string
+	every          int
+	interval       time.Duration
+	keep           int
+	lastGeneration int
+	lastTime       time.Time
+}
+
+// NewAutosaver returns nil when periodic checkpoints are not enabled.
+func NewAutosaver(parameters *UsageParameters, generation int) *Autosaver {
+	if *parameters.autosaveEvery <= 0 && *parameters.autosaveInterval <= 0 {
+		return nil
+	}
+
+	return &Autosaver{
+		dir:            *parameters.autosaveDir,
+		every:          *parameters.autosaveEvery,
+		interval:       *parameters.autosaveInterval,
+		keep:           max(*parameters.autosaveKeep, 1),
+		lastGeneration: generation,
+		lastTime:       time.Now(),
+	}
+}
+
+// Tick writes a checkpoint when it is due and returns its file name.
+func (a *Autosaver) Tick(game *Game, parameters *UsageParameters) (string, error) {
+	generation := game.Universe.Generation()
+	due := a.every > 0 && generation-a.lastGeneration >= a.every ||
+		a.interval > 0 && time.Since(a.lastTime) >= a.interval
+	if !due {
+		return "", nil
+	}
+	return a.Save(game, parameters)
+}
+
+// Save writes a checkpoint and removes the oldest ones keeping the newest --autosave-keep files.
+func (a *Autosaver) Save(game *Game, parameters *UsageParameters) (string, error) {
+	a.lastGeneration = game.Universe.Generation()
+	a.lastTime = time.Now()
+
+	if err := os.MkdirAll(a.dir, 0o755); err != nil {
+		return "", err
+	}
+
+	target := filepath.Join(a.dir, fmt.Sprintf("checkpoint-%s-gen%d%s",
+		a.lastTime.Format("20060102-150405"), a.lastGeneration, checkpointExt))
+	// Write into a temporary file first, so a kill in the middle doesn't leave a broken checkpoint.
+	if err := game.SaveSnapshot(target+".tmp", parameters); err != nil {
+		return "", err
+	}
+	if err := os.Rename(target+".tmp", target); err != nil {
+		return "", err
+	}
+
+	checkpoints, err := listCheckpoints(a.dir)
+	if err != nil {
+		return target, err
+	}
+	for len(checkpoints) > a.keep {
+		if err := os.Remove(checkpoints[0]); err != nil {
+			return target, err
+		}
+		checkpoints = checkpoints[1:]
+	}
+
+	return target, nil
+}
+
+// listCheckpoints returns checkpoint files of the directory from the oldest to the newest.
+func listCheckpoints(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	type checkpoint struct {
+		path    string
+		modTime time.Time
+	}
+	var checkpoints []checkpoint
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasPrefix(e.Name(), "checkpoint-") || filepath.Ext(e.Name()) != checkpointExt {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+		checkpoints = append(checkpoints, checkpoint{filepath.Join(dir, e.Name()), info.ModTime()})
+	}
+
+	slices.SortStableFunc(checkpoints, func(a, b checkpoint) int {
+		if c := a.modTime.Compare(b.modTime); c != 0 {
+			return c
+		}
+		return strings.Compare(a.path, b.path)
+	})
+
+	paths := make([]string, len(checkpoints))
+	for i, c := range checkpoints {
+		paths[i] = c.path
+	}
+	return paths, nil
+}
+
+// resolveSnapshot returns the newest checkpoint when the source is a directory.
+func resolveSnapshot(source string) (string, error) {
+	info, err := os.Stat(source)
+	if err != nil || !info.IsDir() {
+		return source, err
+	}
+
+	checkpoints, err := listCheckpoints(source)
+	if err != nil {
+		return "", err
+	}
+	if len(checkpoints) == 0 {
+		return "", fmt.Errorf("no checkpoints found in %s", source)
+	}
+	return checkpoints[len(checkpoints)-1], nil
+}
@@ -57,12 +57,14 @@ func (u *BoardedUniverse) Parameters() UsageParameters {
 
 func (u *BoardedUniverse) SetAliveCell(x int, y int) {
 
+	if x < 0 || x >= u.width || y < 0 || y >= u.height {
+		return
+	}
+
 	oldStatus := u.board[x][y]
-	if x >= 0 && x < u.width && y >= 0 && y < u.height {
-		if u.board[x][y] == 0 {
-			u.board[x][y] = 1
-			u.aliveCount++
-		}
+	if u.board[x][y] == 0 {
+		u.board[x][y] = 1
+		u.aliveCount++
 	}
 	u.setStats(oldStatus, 1)
 }
@@ -115,7 +117,7 @@ func (u *BoardedUniverse) NextStep() {
 func (u *BoardedUniverse) aliveGenerationsOnNextStep(i int, j int) int {
 
 	cnt := u.aliveNeighbours(i, j)
-	if cnt == 3 || (cnt == 2 && u.board[i][j] > 0) {
+	if u.parameters.rule.Next(cnt, u.board[i][j] > 0) {
 		return u.board[i][j] + 1
 	} else {
 		return 0
@@ -167,3 +169,27 @@ func (u *BoardedUniverse) GameBounds() Bounds {
 func (u *BoardedUniverse) Stats() map[int]UniverseStats {
 	return u.stats
 }
+
+func (u *BoardedUniverse) Restore(generation int, cells map[Coord]int, stats map[int]UniverseStats) {
+	u.aliveCount = 0
+	for i := range u.board {
+		for j := range u.board[i] {
+			u.board[i][j] = cells[Coord{i, j}]
+			if u.board[i][j] > 0 {
+				u.aliveCount++
+			}
+		}
+	}
+	u.generation = generation
+	u.stats = stats
+}
+
+func (u *BoardedUniverse) ForEachAlive(fn func(cell Coord, age int)) {
+	for i := range u.board {
+		for j, age := range u.board[i] {
+			if age > 0 {
+				fn(Coord{i, j}, age)
+			}
+		}
+	}
+}
@@ -0,0 +1,269 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nsf/termbox-go"
+	"golang.org/x/term"
+)
+
+const (
+	DefaultCatalogDir = "objects"
+	RootCatalogName   = "misc"
+	catalogListWidth  = 36
+)
+
+type catalogEntry struct {
+	category string
+	path     string
+	name     string
+	pattern  Pattern
+}
+
+// catalogLine is a single row of the list pane, either a category header
+// or a reference to a catalog entry.
+type catalogLine struct {
+	header string
+	entry  int
+}
+
+type CatalogBrowser struct {
+	entries     []catalogEntry
+	lines       []catalogLine
+	selected    int
+	scroll      int
+	symbolAlive rune
+	theme       *Theme
+	colorMode   termbox.OutputMode
+}
+
+func NewCatalogBrowser(root string, symbolAlive rune, theme *Theme, colorMode termbox.OutputMode) (*CatalogBrowser, error) {
+	entries, err := readCatalog(root)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &CatalogBrowser{entries: entries, symbolAlive: symbolAlive, theme: theme, colorMode: colorMode}
+	category := ""
+	for i, e := range entries {
+		if i == 0 || e.category != category {
+			category = e.category
+			b.lines = append(b.lines, catalogLine{header: category, entry: -1})
+		}
+		b.lines = append(b.lines, catalogLine{entry: i})
+	}
+	b.moveSelection(1)
+
+	return b, nil
+}
+
+func readCatalog(root string) ([]catalogEntry, error) {
+	var entries []catalogEntry
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || formatExtensions[strings.ToLower(filepath.Ext(path))] == "" {
+			return nil
+		}
+
+		category := RootCatalogName
+		if dir, _ := filepath.Rel(root, filepath.Dir(path)); dir != "." {
+			category = filepath.ToSlash(dir)
+		}
+
+		pattern, err := loadPattern(path)
+		if err != nil {
+			return err
+		}
+		entry := catalogEntry{
+			category: category,
+			path:     path,
+			name:     pattern.Metadata.Name,
+			pattern:  pattern,
+		}
+		if entry.name == "" {
+			entry.name = strings.TrimSuffix(d.Name(), filepath.Ext(path))
+		}
+		entries = append(entries, entry)
+
+		return nil
+	})
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].category != entries[j].category {
+			return entries[i].category < entries[j].category
+		}
+		return strings.ToLower(entries[i].name) < strings.ToLower(entries[j].name)
+	})
+
+	return entries, err
+}
+
+// Browse shows the catalog and blocks until a pattern is picked with <ENTER>
+// or the browser is closed with <ESC>.
+func (b *CatalogBrowser) Browse(events <-chan termbox.Event) (string, bool) {
+	if len(b.entries) == 0 {
+		return "", false
+	}
+
+	for {
+		b.draw()
+
+		ev := <-events
+		if ev.Type != termbox.EventKey {
+			continue
+		}
+
+		if ev.Key == termbox.KeyEsc {
+			return "", false
+		} else if ev.Key == termbox.KeyEnter {
+			return b.entries[b.lines[b.selected].entry].path, true
+		} else if ev.Key == termbox.KeyArrowUp {
+			b.moveSelection(-1)
+		} else if ev.Key == termbox.KeyArrowDown {
+			b.moveSelection(1)
+		} else if ev.Key == termbox.KeyPgup {
+			b.moveSelection(-10)
+		} else if ev.Key == termbox.KeyPgdn {
+			b.moveSelection(10)
+		}
+	}
+}
+
+// moveSelection moves the cursor by delta entries skipping category headers.
+func (b *CatalogBrowser) moveSelection(delta int) {
+	step := 1
+	if delta < 0 {
+		step = -1
+		delta = -delta
+	}
+
+	for ; delta > 0; delta-- {
+		next := b.selected + step
+		for next >= 0 && next < len(b.lines) && b.lines[next].entry < 0 {
+			next += step
+		}
+		if next < 0 || next >= len(b.lines) {
+			break
+		}
+		b.selected = next
+	}
+}
+
+func (b *CatalogBrowser) draw() {
+	width, height, _ := term.GetSize(int(os.Stdout.Fd()))
+	screen := NewScreen(width, height)
+	listWidth := min(catalogListWidth, width/3)
+
+	textFg, textBg := b.theme.Text.colors(b.colorMode)
+	screen.DrawString(1, 0, " Catalog: <UP>/<DOWN> select, <ENTER> pick, <ESC> close ", textFg, textBg)
+	b.drawList(screen, 1, 2, listWidth-1, height-3)
+	b.drawDetails(screen, listWidth+2, 2, width-listWidth-3, height-3)
+
+	err := screen.Flush()
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (b *CatalogBrowser) drawList(screen *Screen, x int, y int, width int, height int) {
+	if b.selected < b.scroll {
+		b.scroll = b.selected
+	}
+	if b.selected >= b.scroll+height {
+		b.scroll = b.selected - height + 1
+	}
+
+	headerFg, headerBg := b.theme.Status.colors(b.colorMode)
+	for i := 0; i < height && b.scroll+i < len(b.lines); i++ {
+		line := b.lines[b.scroll+i]
+		if line.entry < 0 {
+			screen.DrawString(x, y+i, truncate(line.header+"/", width), headerFg, headerBg)
+			continue
+		}
+
+		fg, bg := b.theme.Text.colors(b.colorMode)
+		if b.scroll+i == b.selected {
+			fg, bg = b.theme.Cursor.colors(b.colorMode)
+		}
+		screen.DrawString(x, y+i, truncate("  "+b.entries[line.entry].name, width), fg, bg)
+	}
+}
+
+func (b *CatalogBrowser) drawDetails(screen *Screen, x int, y int, width int, height int) {
+	entry := b.entries[b.lines[b.selected].entry]
+
+	aliveFg, _ := b.theme.aliveStyle(1).colors(b.colorMode)
+	textFg, textBg := b.theme.Text.colors(b.colorMode)
+	borderFg, borderBg := b.theme.Border.colors(b.colorMode)
+	screen.DrawString(x, y, truncate(entry.name, width), aliveFg|termbox.AttrBold, textBg)
+	screen.DrawString(x, y+1, truncate(entry.path, width), borderFg, borderBg)
+	row := y + 3
+	if entry.pattern.Metadata.Author != "" {
+		screen.DrawString(x, row, truncate("Author: "+entry.pattern.Metadata.Author, width), textFg, textBg)
+		row++
+	}
+	for _, comment := range entry.pattern.Metadata.Comments {
+		if row >= y+height/2 {
+			break
+		}
+		screen.DrawString(x, row, truncate(comment, width), textFg, textBg)
+		row++
+	}
+
+	row++
+	screen.DrawString(x, row, truncate(" Preview ", width), textFg|termbox.AttrReverse, textBg)
+	row++
+	b.drawPreview(screen, entry.pattern, x, row, width, y+height-row)
+}
+
+// drawPreview renders the pattern thumbnail, large patterns are scaled down
+// so that one character represents a square block of cells.
+func (b *CatalogBrowser) drawPreview(screen *Screen, pattern Pattern, x int, y int, width int, height int) {
+	patternWidth, patternHeight := pattern.size()
+	if width <= 0 || height <= 0 || patternWidth == 0 {
+		return
+	}
+
+	scale := max((patternWidth+width-1)/width, (patternHeight+height-1)/height, 1)
+	aliveFg, _ := b.theme.aliveStyle(1).colors(b.colorMode)
+	pattern.forEachAlive(func(i int, j int) {
+		screen.SetCell(x+i/scale, y+j/scale, b.symbolAlive, aliveFg, termbox.ColorDefault)
+	})
+}
+
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if width <= 0 {
+		return ""
+	}
+	if len(runes) > width {
+		return string(runes[:width])
+	}
+	return s
+}
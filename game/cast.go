@@ -0,0 +1,113 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/nsf/termbox-go"
+)
+
+type castHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env"`
+}
+
+// CastRecorder writes the drawn frames into asciinema v2 file,
+// every frame is stored as the ANSI difference to the previous one.
+type CastRecorder struct {
+	file     *os.File
+	writer   *bufio.Writer
+	start    time.Time
+	previous *Screen
+	mode     termbox.OutputMode
+}
+
+func NewCastRecorder(target string, width int, height int, title string) (*CastRecorder, error) {
+	file, err := os.Create(target)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &CastRecorder{
+		file:   file,
+		writer: bufio.NewWriter(file),
+		start:  time.Now(),
+		mode:   termbox.OutputNormal,
+	}
+
+	header, err := json.Marshal(castHeader{
+		Version:   2,
+		Width:     width,
+		Height:    height,
+		Timestamp: r.start.Unix(),
+		Title:     title,
+		Env:       map[string]string{"TERM": os.Getenv("TERM"), "SHELL": os.Getenv("SHELL")},
+	})
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	fmt.Fprintf(r.writer, "%s\n", header)
+
+	return r, r.writer.Flush()
+}
+
+func (r *CastRecorder) WriteFrame(screen *Screen) error {
+	elapsed := time.Since(r.start).Seconds()
+
+	if r.previous != nil && (r.previous.Width != screen.Width || r.previous.Height != screen.Height) {
+		if err := r.writeEvent(elapsed, "r", fmt.Sprintf("%dx%d", screen.Width, screen.Height)); err != nil {
+			return err
+		}
+	}
+
+	if err := r.writeEvent(elapsed, "o", screen.ANSI(r.previous, r.mode)); err != nil {
+		return err
+	}
+	r.previous = screen
+
+	return r.writer.Flush()
+}
+
+func (r *CastRecorder) writeEvent(elapsed float64, kind string, data string) error {
+	event, err := json.Marshal([]any{elapsed, kind, data})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(r.writer, "%s\n", event)
+	return err
+}
+
+func (r *CastRecorder) Close() error {
+	err := r.writer.Flush()
+	if closeErr := r.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,343 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	// CensusMaxPeriod is the longest period looked for when an object is classified.
+	CensusMaxPeriod = 256
+	// censusDistance is the largest distance between two cells of the same object,
+	// cells two cells apart still interact through the cell between them.
+	censusDistance = 2
+	wechslerDigits = "0123456789abcdefghijklmnopqrstuv"
+	wechslerZeros  = "0123456789abcdefghijklmnopqrstuvwxyz"
+)
+
+const (
+	ClassStillLife    = "still life"
+	ClassOscillator   = "oscillator"
+	ClassSpaceship    = "spaceship"
+	ClassPathological = "pathological"
+)
+
+// CensusEntry counts the objects of the same canonical code.
+type CensusEntry struct {
+	Code       string `json:"code"`
+	Class      string `json:"class"`
+	Period     int    `json:"period"`
+	Velocity   string `json:"velocity,omitempty"`
+	Population int    `json:"population"`
+	Count      int    `json:"count"`
+	// Dx and Dy are the displacement per period of the classified object as it lies on the board.
+	Dx int `json:"-"`
+	Dy int `json:"-"`
+}
+
+// Census is the list of separate objects of the universe, the most common first.
+type Census struct {
+	Generation int           `json:"generation"`
+	Objects    int           `json:"objects"`
+	Entries    []CensusEntry `json:"entries"`
+}
+
+// TakeCensus separates the alive cells into objects and classifies every object
+// by running it in isolation until its shape repeats.
+func TakeCensus(u Universe) Census {
+	census := Census{Generation: u.Generation()}
+	entries := make(map[string]*CensusEntry)
+
+	parameters := u.Parameters()
+	for _, object := range separateObjects(u) {
+		entry := classifyObject(object, parameters, CensusMaxPeriod)
+		if existing, ok := entries[entry.Code]; ok {
+			existing.Count++
+		} else {
+			entry.Count = 1
+			entries[entry.Code] = &entry
+		}
+		census.Objects++
+	}
+
+	for _, entry := range entries {
+		census.Entries = append(census.Entries, *entry)
+	}
+	slices.SortFunc(census.Entries, func(a, b CensusEntry) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Code, b.Code))
+	})
+
+	return census
+}
+
+// separateObjects groups the alive cells into connected objects.
+func separateObjects(u Universe) [][]Coord {
+	var cells []Coord
+	index := make(map[Coord]int, u.AliveCount())
+	u.ForEachAlive(func(cell Coord, age int) {
+		index[cell] = len(cells)
+		cells = append(cells, cell)
+	})
+
+	parent := make([]int, len(cells))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+
+	for i, c := range cells {
+		for dx := -censusDistance; dx <= censusDistance; dx++ {
+			for dy := -censusDistance; dy <= censusDistance; dy++ {
+				if j, ok := index[Coord{c.X + dx, c.Y + dy}]; ok {
+					parent[find(i)] = find(j)
+				}
+			}
+		}
+	}
+
+	groups := make(map[int][]Coord)
+	for i, c := range cells {
+		root := find(i)
+		groups[root] = append(groups[root], c)
+	}
+	objects := make([][]Coord, 0, len(groups))
+	for _, group := range groups {
+		objects = append(objects, group)
+	}
+	return objects
+}
+
+// classifyObject runs the object alone on the infinite board with the same rule
+// for up to maxPeriod generations.
+func classifyObject(cells []Coord, parameters UsageParameters, maxPeriod int) CensusEntry {
+	u := isolatedUniverse(parameters)
+	for _, c := range cells {
+		u.SetAliveCell(c.X, c.Y)
+	}
+
+	start, startOrigin := normalizeCells(cells)
+	phases := [][][]bool{cellsMatrix(start)}
+	for period := 1; period <= maxPeriod && u.AliveCount() > 0; period++ {
+		u.NextStep()
+
+		current, origin := normalizeCells(aliveCoords(u))
+		if slices.Equal(current, start) {
+			entry := CensusEntry{Period: period, Population: len(cells)}
+			switch {
+			case origin != startOrigin:
+				entry.Class = ClassSpaceship
+				entry.Dx, entry.Dy = origin.X-startOrigin.X, origin.Y-startOrigin.Y
+				entry.Velocity = velocity(entry.Dx, entry.Dy, period)
+				entry.Code = fmt.Sprintf("xq%d_%s", period, canonicalWechsler(phases))
+			case period == 1:
+				entry.Class = ClassStillLife
+				entry.Code = fmt.Sprintf("xs%d_%s", len(cells), canonicalWechsler(phases))
+			default:
+				entry.Class = ClassOscillator
+				entry.Code = fmt.Sprintf("xp%d_%s", period, canonicalWechsler(phases))
+			}
+			return entry
+		}
+		phases = append(phases, cellsMatrix(current))
+	}
+
+	return CensusEntry{
+		Class:      ClassPathological,
+		Code:       fmt.Sprintf("PATHOLOGICAL_%d", len(cells)),
+		Population: len(cells),
+	}
+}
+
+// velocity formats the speed of the displacement per period as a fraction
+// of the speed of light with the direction, e.g. c/4 diagonal or c/2 orthogonal.
+func velocity(dx int, dy int, period int) string {
+	dx, dy = abs(dx), abs(dy)
+	distance := max(dx, dy)
+	divisor := gcd(distance, period)
+	distance, period = distance/divisor, period/divisor
+
+	speed := "c"
+	if distance > 1 {
+		speed = fmt.Sprintf("%dc", distance)
+	}
+	if period > 1 {
+		speed = fmt.Sprintf("%s/%d", speed, period)
+	}
+
+	switch {
+	case dx == 0 || dy == 0:
+		return speed + " orthogonal"
+	case dx == dy:
+		return speed + " diagonal"
+	default:
+		return speed + " oblique"
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func gcd(a int, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// isolatedUniverse creates an empty infinite universe with the rule of the parameters.
+func isolatedUniverse(parameters UsageParameters) *InfiniteUniverse {
+	boardType := "infinite"
+	parameters.boardType = &boardType
+	return CreateUniverseInfinite(&parameters)
+}
+
+func aliveCoords(u Universe) []Coord {
+	cells := make([]Coord, 0, u.AliveCount())
+	u.ForEachAlive(func(cell Coord, age int) {
+		cells = append(cells, cell)
+	})
+	return cells
+}
+
+// normalizeCells moves the cells to the origin and sorts them row by row.
+func normalizeCells(cells []Coord) ([]Coord, Coord) {
+	if len(cells) == 0 {
+		return nil, Coord{}
+	}
+	topLeft := cells[0]
+	for _, c := range cells {
+		topLeft.X = min(topLeft.X, c.X)
+		topLeft.Y = min(topLeft.Y, c.Y)
+	}
+
+	normalized := make([]Coord, len(cells))
+	for i, c := range cells {
+		normalized[i] = Coord{c.X - topLeft.X, c.Y - topLeft.Y}
+	}
+	slices.SortFunc(normalized, func(a, b Coord) int {
+		return cmp.Or(cmp.Compare(a.Y, b.Y), cmp.Compare(a.X, b.X))
+	})
+	return normalized, topLeft
+}
+
+func cellsMatrix(cells []Coord) [][]bool {
+	var p Pattern
+	p.setCells(cells)
+	return p.Cells
+}
+
+// canonicalWechsler returns the shortest, then the alphabetically first extended
+// Wechsler code of all phases in all rotations and reflections.
+func canonicalWechsler(phases [][][]bool) string {
+	best := ""
+	for _, phase := range phases {
+		for _, m := range orientations(phase) {
+			code := wechsler(m)
+			if best == "" || len(code) < len(best) || len(code) == len(best) && code < best {
+				best = code
+			}
+		}
+	}
+	return best
+}
+
+func orientations(m [][]bool) [][][]bool {
+	result := make([][][]bool, 0, 8)
+	for range 4 {
+		result = append(result, m, flipX(m))
+		m = rotate90(m)
+	}
+	return result
+}
+
+// wechsler encodes the matrix in extended Wechsler format: strips of 5 rows
+// separated by z, every column of a strip is a base 32 digit with the top row
+// in the lowest bit, runs of empty columns are shortened to w, x and y.
+func wechsler(m [][]bool) string {
+	width, height := matrixSize(m)
+
+	var strips []string
+	for top := 0; top < height; top += 5 {
+		var digits []byte
+		for x := range width {
+			value := 0
+			for bit := range 5 {
+				if top+bit < height && m[x][top+bit] {
+					value |= 1 << bit
+				}
+			}
+			digits = append(digits, wechslerDigits[value])
+		}
+		strips = append(strips, compressZeros(strings.TrimRight(string(digits), "0")))
+	}
+	return strings.Join(strips, "z")
+}
+
+func compressZeros(strip string) string {
+	var sb strings.Builder
+	for i := 0; i < len(strip); {
+		if strip[i] != '0' {
+			sb.WriteByte(strip[i])
+			i++
+			continue
+		}
+
+		n := 0
+		for i+n < len(strip) && strip[i+n] == '0' {
+			n++
+		}
+		i += n
+		for n >= 4 {
+			run := min(n, 4+len(wechslerZeros)-1)
+			sb.WriteByte('y')
+			sb.WriteByte(wechslerZeros[run-4])
+			n -= run
+		}
+		sb.WriteString([]string{"", "0", "w", "x"}[n])
+	}
+	return sb.String()
+}
+
+// Lines formats the census as a table.
+func (c Census) Lines() []string {
+	lines := []string{
+		fmt.Sprintf("Census of generation %d: %d objects", c.Generation, c.Objects),
+		fmt.Sprintf("%6s  %-12s  %6s  %-14s  %5s  %s", "Count", "Class", "Period", "Velocity", "Cells", "Code"),
+	}
+	for _, e := range c.Entries {
+		lines = append(lines, fmt.Sprintf("%6d  %-12s  %6d  %-14s  %5d  %s", e.Count, e.Class, e.Period, e.Velocity, e.Population, e.Code))
+	}
+	return lines
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import "testing"
+
+func coordsFromRows(lines ...string) []Coord {
+	var cells []Coord
+	for y, line := range lines {
+		for x, ch := range line {
+			if ch == 'o' {
+				cells = append(cells, Coord{x, y})
+			}
+		}
+	}
+	return cells
+}
+
+func TestCanonicalWechsler(t *testing.T) {
+	tests := []struct {
+		name   string
+		phases [][]string
+		want   string
+	}{
+		{"block", [][]string{{"oo", "oo"}}, "33"},
+		{"blinker", [][]string{{"ooo"}, {"o", "o", "o"}}, "7"},
+		{"vertical blinker only", [][]string{{"o", "o", "o"}}, "7"},
+		{"loaf", [][]string{{".oo.", "o..o", ".o.o", "..o."}}, "2596"},
+		{"rotated loaf", [][]string{{".o..", "o.o.", "o..o", ".oo."}}, "2596"},
+		{"glider", [][]string{{".o.", "..o", "ooo"}, {"o.o", ".oo", ".o."}}, "153"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			phases := make([][][]bool, len(test.phases))
+			for i, phase := range test.phases {
+				phases[i] = fromRows(phase...)
+			}
+			if got := canonicalWechsler(phases); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestClassifyObject(t *testing.T) {
+	tests := []struct {
+		name   string
+		rows   []string
+		code   string
+		class  string
+		period int
+	}{
+		{"block", []string{"oo", "oo"}, "xs4_33", ClassStillLife, 1},
+		{"blinker", []string{"ooo"}, "xp2_7", ClassOscillator, 2},
+		{"glider", []string{".o.", "..o", "ooo"}, "xq4_153", ClassSpaceship, 4},
+		{"reflected glider", []string{"ooo", "o..", ".o."}, "xq4_153", ClassSpaceship, 4},
+		{"loaf", []string{".oo.", "o..o", ".o.o", "..o."}, "xs7_2596", ClassStillLife, 1},
+		{"beehive", []string{".oo.", "o..o", ".oo."}, "xs6_696", ClassStillLife, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			entry := classifyObject(coordsFromRows(test.rows...), *testParameters(), CensusMaxPeriod)
+			if entry.Code != test.code || entry.Class != test.class || entry.Period != test.period {
+				t.Errorf("got %s %s period %d, want %s %s period %d",
+					entry.Code, entry.Class, entry.Period, test.code, test.class, test.period)
+			}
+		})
+	}
+}
@@ -0,0 +1,379 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nsf/termbox-go"
+)
+
+const maxCommandHistory = 100
+
+// uiCommand is a command of the ':' command line, files tells whether its
+// arguments are completed as file paths.
+type uiCommand struct {
+	usage string
+	files bool
+	run   func(game *Game, parameters *UsageParameters, args []string) (string, error)
+}
+
+var uiCommands = map[string]uiCommand{
+	"goto":     {"goto <x> <y> - center the view on the cell", false, gotoCommand},
+	"rule":     {"rule [rule] - show or set the rule, e.g. B36/S23", false, ruleCommand},
+	"load":     {"load <path>[@x,y][:transform...] - stamp the pattern into the view or at x,y", true, loadCommand},
+	"save":     {"save [path] [format] - save the current generation, to --save by default", true, saveCommand},
+	"snapshot": {"snapshot [path] - save the session, to --snapshot by default", true, snapshotCommand},
+	"image":    {"image [path] - write the view as an image, to --image by default", true, imageCommand},
+	"step":     {"step [n] - advance by n generations, 1 by default", false, stepCommand},
+	"speed":    {"speed [duration] - show or set the time between generations, e.g. 20ms", false, speedCommand},
+	"clear":    {"clear - start over with an empty universe", false, clearCommand},
+}
+
+// CommandLine is the vim-like ':' prompt at the bottom of the board.
+type CommandLine struct {
+	active  bool
+	text    []rune
+	hint    string
+	history []string
+	index   int
+}
+
+// Open activates the prompt with an empty line.
+func (c *CommandLine) Open() {
+	c.active = true
+	c.text = nil
+	c.hint = ""
+	c.index = len(c.history)
+}
+
+// HandleKey edits the line, it returns the line when it is entered with <ENTER>.
+func (c *CommandLine) HandleKey(ev termbox.Event) (string, bool) {
+	c.hint = ""
+	switch {
+	case ev.Key == termbox.KeyEsc:
+		c.active = false
+	case ev.Key == termbox.KeyEnter:
+		c.active = false
+		line := strings.TrimSpace(string(c.text))
+		if line != "" && (len(c.history) == 0 || c.history[len(c.history)-1] != line) {
+			c.history = append(c.history, line)
+			if len(c.history) > maxCommandHistory {
+				c.history = c.history[1:]
+			}
+		}
+		return line, line != ""
+	case ev.Key == termbox.KeyBackspace || ev.Key == termbox.KeyBackspace2:
+		if len(c.text) == 0 {
+			c.active = false
+		} else {
+			c.text = c.text[:len(c.text)-1]
+		}
+	case ev.Key == termbox.KeyCtrlU:
+		c.text = nil
+	case ev.Key == termbox.KeyArrowUp:
+		if c.index > 0 {
+			c.index--
+			c.text = []rune(c.history[c.index])
+		}
+	case ev.Key == termbox.KeyArrowDown:
+		if c.index < len(c.history)-1 {
+			c.index++
+			c.text = []rune(c.history[c.index])
+		} else {
+			c.index = len(c.history)
+			c.text = nil
+		}
+	case ev.Key == termbox.KeyTab:
+		c.complete()
+	case ev.Key == termbox.KeySpace:
+		c.text = append(c.text, ' ')
+	case ev.Ch != 0:
+		c.text = append(c.text, ev.Ch)
+	}
+	return "", false
+}
+
+// complete extends the last word to the longest common prefix of the command
+// names or the file paths, the candidates are shown as the hint.
+func (c *CommandLine) complete() {
+	line := string(c.text)
+	start := strings.LastIndex(line, " ") + 1
+	word := line[start:]
+
+	var candidates []string
+	if fields := strings.Fields(line[:start]); len(fields) == 0 {
+		for _, name := range slices.Sorted(maps.Keys(uiCommands)) {
+			if strings.HasPrefix(name, word) {
+				candidates = append(candidates, name+" ")
+			}
+		}
+	} else if command, ok := uiCommands[fields[0]]; ok && command.files {
+		candidates = completePath(word)
+	}
+	if len(candidates) == 0 {
+		c.hint = "no completions"
+		return
+	}
+
+	prefix := candidates[0]
+	for _, candidate := range candidates[1:] {
+		for !strings.HasPrefix(candidate, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	c.text = []rune(line[:start] + prefix)
+	if len(candidates) > 1 {
+		names := make([]string, len(candidates))
+		for i, candidate := range candidates {
+			names[i] = filepath.Base(strings.TrimSpace(candidate))
+		}
+		c.hint = strings.Join(names, " ")
+	}
+}
+
+// completePath returns the paths starting with the word, directories end with a slash.
+func completePath(word string) []string {
+	dir, prefix := filepath.Split(word)
+	entries, err := os.ReadDir(filepath.Join(".", dir))
+	if err != nil {
+		return nil
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, prefix) || (strings.HasPrefix(name, ".") && !strings.HasPrefix(prefix, ".")) {
+			continue
+		}
+		if entry.IsDir() {
+			paths = append(paths, dir+name+"/")
+		} else {
+			paths = append(paths, dir+name+" ")
+		}
+	}
+	return paths
+}
+
+// ExecuteCommand runs the command line and returns the message for the status.
+func ExecuteCommand(game *Game, parameters *UsageParameters, line string) string {
+	fields := strings.Fields(line)
+	command, ok := uiCommands[fields[0]]
+	if !ok {
+		return fmt.Sprintf("Unknown command %q, press ? for the commands", fields[0])
+	}
+	message, err := command.run(game, parameters, fields[1:])
+	if errors.Is(err, errUsage) {
+		return "Usage: " + command.usage
+	}
+	if err != nil {
+		return err.Error()
+	}
+	return message
+}
+
+func gotoCommand(game *Game, parameters *UsageParameters, args []string) (string, error) {
+	if len(args) != 2 {
+		return "", errUsage
+	}
+	x, y, err := parseCoordArgs(args[0], args[1])
+	if err != nil {
+		return "", err
+	}
+	view := game.VisibleBounds()
+	game.ResetOrigin(Coord{x - (view.BottomRight.X-view.TopLeft.X)/2, y - (view.BottomRight.Y-view.TopLeft.Y)/2})
+	return fmt.Sprintf("Centered on x=%d y=%d", x, y), nil
+}
+
+func ruleCommand(game *Game, parameters *UsageParameters, args []string) (string, error) {
+	if len(args) > 1 {
+		return "", errUsage
+	}
+	if len(args) == 1 {
+		rule, err := ParseRule(args[0])
+		if err != nil {
+			return "", err
+		}
+		if err := game.SetRule(rule, parameters); err != nil {
+			return "", err
+		}
+	}
+	return "Rule " + game.Universe.Parameters().rule.String(), nil
+}
+
+func loadCommand(game *Game, parameters *UsageParameters, args []string) (string, error) {
+	if len(args) != 1 {
+		return "", errUsage
+	}
+	placement, err := parsePlacement(args[0])
+	if err != nil {
+		return "", err
+	}
+	pattern, err := loadPattern(placement.path)
+	if err != nil {
+		return "", err
+	}
+
+	game.Metadata = game.Metadata.merge(pattern.Metadata)
+	transformed := placement.apply(pattern)
+	if placement.offset != nil {
+		game.embedPatternAt(transformed, placement.offset.X, placement.offset.Y)
+	} else {
+		game.Stamp(transformed)
+	}
+	return "Loaded " + placement.path, nil
+}
+
+func saveCommand(game *Game, parameters *UsageParameters, args []string) (string, error) {
+	if len(args) > 2 {
+		return "", errUsage
+	}
+	target, format := parameters.saveTarget(game.Universe.Generation()), *parameters.saveFormat
+	if len(args) > 0 {
+		target = args[0]
+	}
+	if len(args) > 1 {
+		format = args[1]
+	}
+	if err := game.Save(target, format); err != nil {
+		return "", err
+	}
+	return "Saved " + target, nil
+}
+
+func snapshotCommand(game *Game, parameters *UsageParameters, args []string) (string, error) {
+	if len(args) > 1 {
+		return "", errUsage
+	}
+	target := *parameters.snapshot
+	if len(args) > 0 {
+		target = args[0]
+	}
+	if err := game.SaveSnapshot(target, parameters); err != nil {
+		return "", err
+	}
+	return "Saved session to " + target, nil
+}
+
+func imageCommand(game *Game, parameters *UsageParameters, args []string) (string, error) {
+	if len(args) > 1 {
+		return "", errUsage
+	}
+	target := parameters.imageTarget(game.Universe.Generation())
+	if len(args) > 0 {
+		target = args[0]
+	}
+	if err := game.SaveImage(target, parameters, game.VisibleBounds()); err != nil {
+		return "", err
+	}
+	return "Saved " + target, nil
+}
+
+func stepCommand(game *Game, parameters *UsageParameters, args []string) (string, error) {
+	if len(args) > 1 {
+		return "", errUsage
+	}
+	n, err := countArg(args, 1, MaxCount)
+	if err != nil {
+		return "", err
+	}
+	for range n {
+		if game.Universe.AliveCount() == 0 {
+			break
+		}
+		game.Universe.NextStep()
+		game.recordActivity()
+	}
+	return fmt.Sprintf("Generation %d", game.Universe.Generation()), nil
+}
+
+func speedCommand(game *Game, parameters *UsageParameters, args []string) (string, error) {
+	if len(args) > 1 {
+		return "", errUsage
+	}
+	if len(args) == 1 {
+		sleep, err := time.ParseDuration(args[0])
+		if err != nil || sleep < SpeedIncrement {
+			return "", fmt.Errorf("invalid speed %q, expected a duration of at least %s", args[0], SpeedIncrement)
+		}
+		*parameters.sleep = sleep
+	}
+	return fmt.Sprintf("Speed %s per generation", *parameters.sleep), nil
+}
+
+func clearCommand(game *Game, parameters *UsageParameters, args []string) (string, error) {
+	if len(args) > 0 {
+		return "", errUsage
+	}
+	width, height := parameters.screenSize()
+	u, err := createUniverse(*parameters.boardType, width, height, parameters)
+	if err != nil {
+		return "", err
+	}
+	game.Universe = u
+	game.Metadata = PatternMetadata{}
+	if game.activity != nil {
+		game.activity = newActivityMap(u, game.activity.window)
+	}
+	return "Cleared", nil
+}
+
+// settingsHelpLines lists the current settings and the commands for the second page of the help.
+func settingsHelpLines(game *Game, parameters *UsageParameters) []string {
+	colors := map[termbox.OutputMode]string{termbox.OutputRGB: "truecolor", termbox.Output256: "256 colours"}[game.colorMode]
+	if colors == "" {
+		colors = "16 colours"
+	}
+	heatmap := "off"
+	if game.activity != nil {
+		heatmap = fmt.Sprintf("%d generations", game.activity.window)
+	}
+	gens := "infinite"
+	if *parameters.gens > 0 {
+		gens = fmt.Sprint(*parameters.gens)
+	}
+
+	lines := []string{
+		"Settings",
+		"",
+		"Rule:       " + parameters.rule.String(),
+		"Board:      " + *parameters.boardType,
+		fmt.Sprintf("Speed:      %s per generation", *parameters.sleep),
+		"Run until:  " + gens,
+		"Theme:      " + parameters.colorTheme().Name + ", " + colors,
+		"Heat map:   " + heatmap,
+		"Save to:    " + parameters.saveTarget(game.Universe.Generation()),
+		"Session:    " + *parameters.snapshot,
+		"",
+		"Commands, type : and complete with <TAB>",
+		"",
+	}
+	for _, name := range slices.Sorted(maps.Keys(uiCommands)) {
+		lines = append(lines, uiCommands[name].usage)
+	}
+	return lines
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import "testing"
+
+func TestCommandLineComplete(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+		hint string
+	}{
+		{"", "", "clear goto image load rule save snapshot speed step"},
+		{" ", " ", "clear goto image load rule save snapshot speed step"},
+		{"go", "goto ", ""},
+		{"  ru", "  rule ", ""},
+		{"s", "s", "save snapshot speed step"},
+		{"sn", "snapshot ", ""},
+		{"x", "x", "no completions"},
+		{"rule B3", "rule B3", "no completions"},
+		{"goto 1 ", "goto 1 ", "no completions"},
+	}
+
+	for _, test := range tests {
+		c := CommandLine{text: []rune(test.line)}
+		c.complete()
+		if string(c.text) != test.want || c.hint != test.hint {
+			t.Errorf("%q: got %q with hint %q, want %q with hint %q", test.line, string(c.text), c.hint, test.want, test.hint)
+		}
+	}
+}
+
+func TestStepPastGens(t *testing.T) {
+	parameters := testParameters()
+	gens := 10
+	parameters.gens = &gens
+	game := Game{Universe: CreateUniverseInfinite(parameters)}
+	game.embedPatternAt(Pattern{Cells: fromRows("ooo")}, 0, 0)
+
+	if _, err := stepCommand(&game, parameters, []string{"15"}); err != nil {
+		t.Fatal(err)
+	}
+	if generation := game.Universe.Generation(); generation != 15 || !parameters.gensReached(generation) {
+		t.Errorf("generation %d: the run of %d generations is not over", generation, gens)
+	}
+	if parameters.gensReached(gens - 1) {
+		t.Errorf("generation %d: the run of %d generations is over", gens-1, gens)
+	}
+}
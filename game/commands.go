@@ -0,0 +1,605 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const DefaultStableLimit = 10000
+
+// errUsage is replaced by the usage of the command in the reply.
+var errUsage = errors.New("invalid arguments")
+
+// commandReply is a structured response of a protocol command, written as one JSON line.
+type commandReply map[string]any
+
+type protocolCommand struct {
+	usage string
+	run   func(p *CommandProcessor, args []string) (commandReply, error)
+}
+
+var protocolCommands = map[string]protocolCommand{
+	"load":             {"load <path>[@x,y][:transform...] - put the pattern file into the universe", (*CommandProcessor).load},
+	"paste":            {"paste <x> <y> <rle> - put the RLE pattern with the top left corner at x,y", (*CommandProcessor).paste},
+	"set":              {"set <x> <y> [0|1] - make the cell alive or dead", (*CommandProcessor).set},
+	"clear":            {"clear - start over with an empty universe", (*CommandProcessor).clear},
+	"step":             {"step [n] - advance the universe by n generations, 1 by default", (*CommandProcessor).step},
+	"run-until-stable": {"run-until-stable [max] - run until the pattern repeats itself or dies out", (*CommandProcessor).runUntilStable},
+	"bbox":             {"bbox - bounding box of the alive cells", (*CommandProcessor).bbox},
+	"population":       {"population - number of the alive cells", (*CommandProcessor).population},
+	"dump":             {"dump [rle|cells|life105|life106|mc] - the alive cells as a pattern", (*CommandProcessor).dump},
+	"rule":             {"rule [rule] - get or set the rule", (*CommandProcessor).rule},
+	"hash":             {"hash - hash of the alive cells, equal for equal generations", (*CommandProcessor).hash},
+	"save":             {"save <path> [format] - write the alive cells as a pattern, the format is chosen by the extension by default", (*CommandProcessor).save},
+	"snapshot":         {"snapshot <path> - write the whole session for --resume", (*CommandProcessor).snapshot},
+	"image":            {"image <path> [x,y,width,height] - write PNG or SVG image of the viewport or the game bounds", (*CommandProcessor).image},
+	"assert":           {"assert <population|generation|x|y|width|height> <op> <n> | assert bbox <x> <y> <width> <height> - fail unless true", (*CommandProcessor).assert},
+	"print":            {"print [text] - reply with the text, the generation and the population", (*CommandProcessor).print},
+	"census":           {"census - separate objects counted by their apgcode with the class and the period", (*CommandProcessor).census},
+	"quit":             {"quit - stop reading the commands", (*CommandProcessor).quit},
+}
+
+// CommandProcessor executes the line-oriented commands of the scripting protocol,
+// it works with the Universe interface only so any engine can be driven by it.
+type CommandProcessor struct {
+	game       *Game
+	parameters *UsageParameters
+	dir        string
+	done       bool
+}
+
+func NewCommandProcessor(game *Game, parameters *UsageParameters) *CommandProcessor {
+	return &CommandProcessor{game: game, parameters: parameters}
+}
+
+// runCommandProtocol reads the commands from stdin and writes the replies to stdout.
+func runCommandProtocol(parameters *UsageParameters) {
+	game := newProtocolGame(parameters)
+	p := NewCommandProcessor(&game, parameters)
+	if err := p.Serve(os.Stdin, os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// runScript executes the script file and exits with status 1 when a command fails.
+func runScript(parameters *UsageParameters) {
+	game := newProtocolGame(parameters)
+	p := NewCommandProcessor(&game, parameters)
+	if err := p.RunScript(*parameters.script, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// newProtocolGame starts with an empty universe unless layout files or a snapshot are given.
+func newProtocolGame(parameters *UsageParameters) Game {
+	if len(*parameters.files) > 0 || *parameters.scene != "" || *parameters.resume != "" {
+		return NewGame(parameters)
+	}
+
+	width, height := parameters.screenSize()
+	u, err := createUniverse(*parameters.boardType, width, height, parameters)
+	if err != nil {
+		fmt.Printf("Invalid board-type specified: %s\n", *parameters.boardType)
+		os.Exit(3)
+	}
+	return Game{Universe: u}
+}
+
+// Serve executes the commands line by line until quit or the end of the input.
+func (p *CommandProcessor) Serve(r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxPatternSize)
+	bw := bufio.NewWriter(w)
+	encoder := json.NewEncoder(bw)
+	encoder.SetEscapeHTML(false)
+
+	for !p.done && scanner.Scan() {
+		reply := p.Execute(scanner.Text())
+		if reply == nil {
+			continue
+		}
+		if err := encoder.Encode(reply); err != nil {
+			return err
+		}
+		if err := bw.Flush(); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// RunScript executes the commands of the file and writes the replies, it stops
+// at the first failed command. Patterns are loaded and files are written relative to the script.
+func (p *CommandProcessor) RunScript(source string, w io.Writer) error {
+	file, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	p.dir = filepath.Dir(source)
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxPatternSize)
+	lineNo := 0
+	for !p.done && scanner.Scan() {
+		lineNo++
+		reply := p.Execute(scanner.Text())
+		if reply == nil {
+			continue
+		}
+		if reply["ok"] == false {
+			return fmt.Errorf("%s:%d: %s: %s", source, lineNo, reply["command"], reply["error"])
+		}
+		if err := encoder.Encode(reply); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// Execute runs a single command line, empty lines and # comments return nil.
+func (p *CommandProcessor) Execute(line string) commandReply {
+	fields := strings.Fields(line)
+	if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+		return nil
+	}
+
+	name, args := fields[0], fields[1:]
+	var reply commandReply
+	var err error
+	if name == "help" {
+		reply = p.help()
+	} else if command, ok := protocolCommands[name]; ok {
+		reply, err = command.run(p, args)
+		if errors.Is(err, errUsage) {
+			err = errors.New("usage: " + command.usage)
+		}
+	} else {
+		err = fmt.Errorf("unknown command %q, try help", name)
+	}
+
+	if err != nil {
+		return commandReply{"ok": false, "command": name, "error": err.Error()}
+	}
+	if reply == nil {
+		reply = commandReply{}
+	}
+	reply["ok"] = true
+	reply["command"] = name
+	return reply
+}
+
+func (p *CommandProcessor) help() commandReply {
+	usage := []string{"help - list the commands"}
+	for _, name := range slices.Sorted(maps.Keys(protocolCommands)) {
+		usage = append(usage, protocolCommands[name].usage)
+	}
+	return commandReply{"commands": usage}
+}
+
+func (p *CommandProcessor) status() commandReply {
+	u := p.game.Universe
+	return commandReply{"generation": u.Generation(), "population": u.AliveCount()}
+}
+
+// path resolves the path relative to the directory of the running script.
+func (p *CommandProcessor) path(name string) string {
+	if p.dir != "" && !filepath.IsAbs(name) {
+		return filepath.Join(p.dir, name)
+	}
+	return name
+}
+
+func (p *CommandProcessor) load(args []string) (commandReply, error) {
+	if len(args) != 1 {
+		return nil, errUsage
+	}
+	placement, err := parsePlacement(args[0])
+	if err != nil {
+		return nil, err
+	}
+	pattern, err := loadPattern(p.path(placement.path))
+	if err != nil {
+		return nil, err
+	}
+
+	p.game.Metadata = p.game.Metadata.merge(pattern.Metadata)
+	transformed := placement.apply(pattern)
+	if placement.offset != nil {
+		p.game.embedPatternAt(transformed, placement.offset.X, placement.offset.Y)
+	} else if pattern.Origin != nil {
+		p.game.embedPatternAt(transformed, pattern.Origin.X, pattern.Origin.Y)
+	} else {
+		p.game.embedPatternAt(transformed, 0, 0)
+	}
+	return p.status(), nil
+}
+
+func (p *CommandProcessor) paste(args []string) (commandReply, error) {
+	if len(args) != 3 {
+		return nil, errUsage
+	}
+	x, y, err := parseCoordArgs(args[0], args[1])
+	if err != nil {
+		return nil, err
+	}
+	pattern, err := parseRLE(strings.NewReader(args[2]), defaultPatternLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	p.game.embedMatrixAt(pattern.Cells, x, y)
+	return p.status(), nil
+}
+
+func (p *CommandProcessor) set(args []string) (commandReply, error) {
+	if len(args) != 2 && len(args) != 3 {
+		return nil, errUsage
+	}
+	x, y, err := parseCoordArgs(args[0], args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	u := p.game.Universe
+	if len(args) == 2 || args[2] == "1" {
+		u.SetAliveCell(x, y)
+	} else if args[2] == "0" {
+		removeCells(u, map[Coord]bool{{x, y}: true})
+	} else {
+		return nil, fmt.Errorf("invalid state %q, expected 0 or 1", args[2])
+	}
+	return p.status(), nil
+}
+
+func parseCoordArgs(xArg string, yArg string) (int, int, error) {
+	x, err := strconv.Atoi(xArg)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid x %q", xArg)
+	}
+	y, err := strconv.Atoi(yArg)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid y %q", yArg)
+	}
+	return x, y, nil
+}
+
+func (p *CommandProcessor) clear(args []string) (commandReply, error) {
+	width, height := p.parameters.screenSize()
+	u, err := createUniverse(*p.parameters.boardType, width, height, p.parameters)
+	if err != nil {
+		return nil, err
+	}
+	p.game.Universe = u
+	p.game.Metadata = PatternMetadata{}
+	return p.status(), nil
+}
+
+func (p *CommandProcessor) step(args []string) (commandReply, error) {
+	n, err := countArg(args, 1, maxStepsPerRequest)
+	if err != nil {
+		return nil, err
+	}
+	for range n {
+		p.game.Universe.NextStep()
+	}
+	return p.status(), nil
+}
+
+// countArg parses the optional count up to the limit, so a typo cannot block for hours.
+func countArg(args []string, fallback int, limit int) (int, error) {
+	if len(args) == 0 {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < 0 || n > limit {
+		return 0, fmt.Errorf("invalid count %q, expected 0-%d", args[0], limit)
+	}
+	return n, nil
+}
+
+// runUntilStable advances the universe until the shape of the alive cells
+// repeats itself, the period and the displacement of the repetition are returned.
+func (p *CommandProcessor) runUntilStable(args []string) (commandReply, error) {
+	limit, err := countArg(args, DefaultStableLimit, maxStepsPerRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	type seenShape struct {
+		generation int
+		topLeft    Coord
+		cells      map[Coord]bool
+	}
+	u := p.game.Universe
+	seen := make(map[uint64][]seenShape)
+	start := u.Generation()
+	for {
+		hash, bounds := shapeHash(u)
+		cells := make(map[Coord]bool, u.AliveCount())
+		u.ForEachAlive(func(cell Coord, age int) {
+			cells[Coord{cell.X - bounds.TopLeft.X, cell.Y - bounds.TopLeft.Y}] = true
+		})
+
+		// Different shapes may have the same hash, so the stored shapes are compared.
+		repeated := slices.IndexFunc(seen[hash], func(shape seenShape) bool {
+			return maps.Equal(shape.cells, cells)
+		})
+		if repeated >= 0 || u.AliveCount() == 0 {
+			reply := p.status()
+			reply["stable"] = true
+			reply["extinct"] = u.AliveCount() == 0
+			if repeated >= 0 {
+				previous := seen[hash][repeated]
+				reply["period"] = u.Generation() - previous.generation
+				reply["dx"] = bounds.TopLeft.X - previous.topLeft.X
+				reply["dy"] = bounds.TopLeft.Y - previous.topLeft.Y
+			}
+			return reply, nil
+		}
+		if u.Generation()-start >= limit {
+			reply := p.status()
+			reply["stable"] = false
+			return reply, nil
+		}
+		seen[hash] = append(seen[hash], seenShape{u.Generation(), bounds.TopLeft, cells})
+		u.NextStep()
+	}
+}
+
+func (p *CommandProcessor) bbox(args []string) (commandReply, error) {
+	bounds := aliveBounds(captureCells(p.game.Universe))
+	if bounds.isEmpty() {
+		return commandReply{"empty": true}, nil
+	}
+	return commandReply{
+		"empty":  false,
+		"x":      bounds.TopLeft.X,
+		"y":      bounds.TopLeft.Y,
+		"width":  bounds.BottomRight.X - bounds.TopLeft.X + 1,
+		"height": bounds.BottomRight.Y - bounds.TopLeft.Y + 1,
+	}, nil
+}
+
+func (p *CommandProcessor) population(args []string) (commandReply, error) {
+	return p.status(), nil
+}
+
+func (p *CommandProcessor) dump(args []string) (commandReply, error) {
+	format := FormatRLE
+	if len(args) > 0 {
+		format = args[0]
+	}
+	if !slices.Contains([]string{FormatCells, FormatRLE, FormatLife105, FormatLife106, FormatMacrocell}, format) {
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+
+	pattern := patternFromUniverse(p.game.Universe, p.game.Metadata)
+	pattern.Rule = p.parameters.rule.String()
+	var sb strings.Builder
+	if err := writePattern(&sb, format, pattern); err != nil {
+		return nil, err
+	}
+	return commandReply{"format": format, "pattern": sb.String()}, nil
+}
+
+func (p *CommandProcessor) rule(args []string) (commandReply, error) {
+	if len(args) > 0 {
+		rule, err := ParseRule(args[0])
+		if err != nil {
+			return nil, err
+		}
+		if err := p.game.SetRule(rule, p.parameters); err != nil {
+			return nil, err
+		}
+	}
+	return commandReply{"rule": p.game.Universe.Parameters().rule.String()}, nil
+}
+
+func (p *CommandProcessor) hash(args []string) (commandReply, error) {
+	shape, _ := shapeHash(p.game.Universe)
+	reply := p.status()
+	reply["hash"] = fmt.Sprintf("%016x", cellsHash(p.game.Universe, Coord{0, 0}))
+	reply["shapeHash"] = fmt.Sprintf("%016x", shape)
+	return reply, nil
+}
+
+func (p *CommandProcessor) save(args []string) (commandReply, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return nil, errUsage
+	}
+	format := ""
+	if len(args) == 2 {
+		format = args[1]
+	}
+	target := p.path(args[0])
+	if err := p.game.Save(target, format); err != nil {
+		return nil, err
+	}
+	reply := p.status()
+	reply["path"] = target
+	return reply, nil
+}
+
+func (p *CommandProcessor) snapshot(args []string) (commandReply, error) {
+	if len(args) != 1 {
+		return nil, errUsage
+	}
+	target := p.path(args[0])
+	if err := p.game.SaveSnapshot(target, p.parameters); err != nil {
+		return nil, err
+	}
+	reply := p.status()
+	reply["path"] = target
+	return reply, nil
+}
+
+// image writes the viewport given as the argument, by --viewport or the game bounds,
+// --crop and the other image options apply as in the headless run.
+func (p *CommandProcessor) image(args []string) (commandReply, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return nil, errUsage
+	}
+	viewport := p.game.Universe.GameBounds()
+	spec := *p.parameters.viewport
+	if len(args) == 2 {
+		spec = args[1]
+	}
+	if spec != AutoViewport {
+		var err error
+		viewport, err = parseViewport(spec)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	target := p.path(args[0])
+	if err := p.game.SaveImage(target, p.parameters, viewport); err != nil {
+		return nil, err
+	}
+	reply := p.status()
+	reply["path"] = target
+	return reply, nil
+}
+
+var assertOperators = map[string]func(a int, b int) bool{
+	"==": func(a int, b int) bool { return a == b },
+	"!=": func(a int, b int) bool { return a != b },
+	"<":  func(a int, b int) bool { return a < b },
+	"<=": func(a int, b int) bool { return a <= b },
+	">":  func(a int, b int) bool { return a > b },
+	">=": func(a int, b int) bool { return a >= b },
+}
+
+func (p *CommandProcessor) assert(args []string) (commandReply, error) {
+	u := p.game.Universe
+	bounds := aliveBounds(captureCells(u))
+
+	if len(args) == 5 && args[0] == "bbox" {
+		expected, err := parseViewport(strings.Join(args[1:], ","))
+		if err != nil {
+			return nil, err
+		}
+		if bounds != expected {
+			return nil, fmt.Errorf("assertion failed: bbox %s, expected %s", formatBounds(bounds), formatBounds(expected))
+		}
+		return p.status(), nil
+	}
+	if len(args) != 3 {
+		return nil, errUsage
+	}
+
+	values := map[string]int{
+		"population": u.AliveCount(),
+		"generation": u.Generation(),
+		"x":          bounds.TopLeft.X,
+		"y":          bounds.TopLeft.Y,
+		"width":      max(bounds.BottomRight.X-bounds.TopLeft.X+1, 0),
+		"height":     max(bounds.BottomRight.Y-bounds.TopLeft.Y+1, 0),
+	}
+	value, ok := values[args[0]]
+	if !ok {
+		return nil, fmt.Errorf("unknown value %q, expected population, generation, x, y, width or height", args[0])
+	}
+	compare, ok := assertOperators[args[1]]
+	if !ok {
+		return nil, fmt.Errorf("unknown operator %q, expected ==, !=, <, <=, > or >=", args[1])
+	}
+	expected, err := strconv.Atoi(args[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid number %q", args[2])
+	}
+	if !compare(value, expected) {
+		return nil, fmt.Errorf("assertion failed: %s %d, expected %s %d", args[0], value, args[1], expected)
+	}
+	return p.status(), nil
+}
+
+func formatBounds(b Bounds) string {
+	if b.isEmpty() {
+		return "empty"
+	}
+	return fmt.Sprintf("%d,%d,%d,%d", b.TopLeft.X, b.TopLeft.Y, b.BottomRight.X-b.TopLeft.X+1, b.BottomRight.Y-b.TopLeft.Y+1)
+}
+
+func (p *CommandProcessor) print(args []string) (commandReply, error) {
+	reply := p.status()
+	reply["message"] = strings.Join(args, " ")
+	return reply, nil
+}
+
+func (p *CommandProcessor) census(args []string) (commandReply, error) {
+	census := TakeCensus(p.game.Universe)
+	reply := p.status()
+	reply["objects"] = census.Objects
+	reply["entries"] = census.Entries
+	return reply, nil
+}
+
+func (p *CommandProcessor) quit(args []string) (commandReply, error) {
+	p.done = true
+	return nil, nil
+}
+
+// cellsHash is FNV-1a of the alive cells relative to the origin in row order.
+func cellsHash(u Universe, origin Coord) uint64 {
+	cells := make(map[Coord]bool, u.AliveCount())
+	u.ForEachAlive(func(cell Coord, age int) {
+		cells[cell] = true
+	})
+
+	h := fnv.New64a()
+	var buf []byte
+	for _, c := range sortedCoords(cells) {
+		buf = strconv.AppendInt(buf[:0], int64(c.X-origin.X), 10)
+		buf = append(buf, ',')
+		buf = strconv.AppendInt(buf, int64(c.Y-origin.Y), 10)
+		buf = append(buf, ';')
+		h.Write(buf)
+	}
+	return h.Sum64()
+}
+
+// shapeHash hashes the alive cells relative to their bounding box,
+// so it does not change when the pattern moves.
+func shapeHash(u Universe) (uint64, Bounds) {
+	bounds := aliveBounds(captureCells(u))
+	if bounds.isEmpty() {
+		return cellsHash(u, Coord{0, 0}), bounds
+	}
+	return cellsHash(u, bounds.TopLeft), bounds
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunScriptRelativePaths(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "glider.rle"), []byte("x = 3, y = 3\nbo$2bo$3o!\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	script := strings.Join([]string{
+		"load glider.rle",
+		"step 4",
+		"save out.rle",
+		"snapshot out.snapshot",
+		"save " + filepath.Join(dir, "absolute.cells"),
+	}, "\n")
+	source := filepath.Join(dir, "test.script")
+	if err := os.WriteFile(source, []byte(script), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	parameters := testParameters()
+	game := Game{Universe: CreateUniverseInfinite(parameters)}
+	if err := NewCommandProcessor(&game, parameters).RunScript(source, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"out.rle", "out.snapshot", "absolute.cells"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("%s was not written next to the script: %v", name, err)
+		}
+	}
+	pattern, err := loadPattern(filepath.Join(dir, "out.rle"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := rows(pattern.Cells); strings.Join(got, "/") != ".o./..o/ooo" {
+		t.Errorf("got %q, want the glider", got)
+	}
+}
+
+func TestRunUntilStable(t *testing.T) {
+	tests := []struct {
+		name    string
+		rle     string
+		period  int
+		dx, dy  int
+		extinct bool
+	}{
+		{"block", "2o$2o!", 1, 0, 0, false},
+		{"blinker", "3o!", 2, 0, 0, false},
+		{"glider", "bo$2bo$3o!", 4, 1, 1, false},
+		{"single cell", "o!", 0, 0, 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parameters := testParameters()
+			game := Game{Universe: CreateUniverseInfinite(parameters)}
+			pattern, err := parseRLE(strings.NewReader(test.rle), defaultPatternLimit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			game.embedPatternAt(pattern, 0, 0)
+
+			reply := NewCommandProcessor(&game, parameters).Execute("run-until-stable")
+			if reply["ok"] != true || reply["stable"] != true || reply["extinct"] != test.extinct {
+				t.Fatalf("got %v, want a stable pattern", reply)
+			}
+			if test.extinct {
+				return
+			}
+			if reply["period"] != test.period || reply["dx"] != test.dx || reply["dy"] != test.dy {
+				t.Errorf("got period %v dx %v dy %v, want %d %d %d", reply["period"], reply["dx"], reply["dy"], test.period, test.dx, test.dy)
+			}
+		})
+	}
+}
+
+func TestStepCountLimit(t *testing.T) {
+	parameters := testParameters()
+	game := Game{Universe: CreateUniverseInfinite(parameters)}
+	p := NewCommandProcessor(&game, parameters)
+
+	for _, line := range []string{"step 1000000000", "step -1", "run-until-stable 1000000000"} {
+		if reply := p.Execute(line); reply["ok"] != false {
+			t.Errorf("%s: got %v, want an error", line, reply)
+		}
+	}
+	if _, err := stepCommand(&game, parameters, []string{"1000000000"}); err == nil {
+		t.Error("step 1000000000: expected an error")
+	}
+}
@@ -0,0 +1,224 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	ConfigEnvPrefix    = "LIFE_"
+	configThemePrefix  = "theme."
+	configKeymapPrefix = "key."
+)
+
+// configEntry is a "key = value" line of the configuration file.
+type configEntry struct {
+	key   string
+	value string
+	line  int
+}
+
+// lifeConfig is the configuration file, its keys are the long flag names,
+// theme.<key> lines overriding the keys of the selected theme and
+// key.<action> lines binding the keys of the game actions.
+type lifeConfig struct {
+	path    string
+	entries []configEntry
+}
+
+// defaultConfigPath returns go-life/config in the user configuration directory,
+// $XDG_CONFIG_HOME or ~/.config on Linux.
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "go-life", "config")
+}
+
+// configEnvName returns the environment variable of the flag, e.g. LIFE_BOARD_TYPE for --board-type.
+func configEnvName(flag string) string {
+	return ConfigEnvPrefix + strings.ToUpper(strings.ReplaceAll(flag, "-", "_"))
+}
+
+// loadConfig reads the configuration file, the missing default file is an empty configuration.
+func loadConfig(path string) (*lifeConfig, error) {
+	explicit := path != ""
+	if !explicit {
+		path = defaultConfigPath()
+	}
+	config := &lifeConfig{path: path}
+	if path == "" {
+		return config, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if !explicit && errors.Is(err, fs.ErrNotExist) {
+			config.path = ""
+			return config, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || text[0] == '#' || text[0] == ';' {
+			continue
+		}
+		key, value, ok := strings.Cut(text, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected key = value", path, line)
+		}
+		config.entries = append(config.entries, configEntry{strings.TrimSpace(key), strings.TrimSpace(value), line})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// apply sets the flags not given on the command line from their environment
+// variables and then from the configuration file, repeatable flags like file
+// take every line of their key.
+func (c *lifeConfig) apply(flags *pflag.FlagSet) error {
+	values := make(map[string][]configEntry)
+	for _, entry := range c.entries {
+		if strings.HasPrefix(entry.key, configThemePrefix) || strings.HasPrefix(entry.key, configKeymapPrefix) {
+			continue
+		}
+		if flags.Lookup(entry.key) == nil || entry.key == "config" {
+			return fmt.Errorf("%s:%d: unknown key %q", c.path, entry.line, entry.key)
+		}
+		values[entry.key] = append(values[entry.key], entry)
+	}
+
+	var err error
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if err != nil || flag.Changed || flag.Name == "config" {
+			return
+		}
+		if value, ok := os.LookupEnv(configEnvName(flag.Name)); ok {
+			if setErr := flags.Set(flag.Name, value); setErr != nil {
+				err = fmt.Errorf("%s: %w", configEnvName(flag.Name), setErr)
+			}
+			return
+		}
+		for _, entry := range values[flag.Name] {
+			if setErr := flags.Set(flag.Name, entry.value); setErr != nil {
+				err = fmt.Errorf("%s:%d: %s: %w", c.path, entry.line, entry.key, setErr)
+				return
+			}
+		}
+	})
+	return err
+}
+
+// applyTheme overrides the theme keys set by theme.<key> lines.
+func (c *lifeConfig) applyTheme(theme *Theme) (*Theme, error) {
+	overridden := *theme
+	for _, entry := range c.entries {
+		key, ok := strings.CutPrefix(entry.key, configThemePrefix)
+		if !ok {
+			continue
+		}
+		if err := overridden.set(key, entry.value, themeFileBase); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", c.path, entry.line, err)
+		}
+	}
+	return &overridden, nil
+}
+
+// applyKeymap binds the keys set by key.<action> lines.
+func (c *lifeConfig) applyKeymap(keymap *Keymap) error {
+	for _, entry := range c.entries {
+		action, ok := strings.CutPrefix(entry.key, configKeymapPrefix)
+		if !ok {
+			continue
+		}
+		if err := keymap.Bind(Action(action), entry.value); err != nil {
+			return fmt.Errorf("%s:%d: %w", c.path, entry.line, err)
+		}
+	}
+	return nil
+}
+
+// print writes the effective configuration in the configuration file format.
+func (c *lifeConfig) print(w io.Writer, flags *pflag.FlagSet, keymap *Keymap) {
+	fmt.Fprintln(w, "# Effective configuration, the precedence is defaults < configuration file < environment < flags")
+	if c.path != "" {
+		fmt.Fprintf(w, "# Configuration file: %s\n", c.path)
+	} else {
+		fmt.Fprintf(w, "# Configuration file: none, the default location is %s\n", defaultConfigPath())
+	}
+
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if flag.Name == "config" || flag.Name == "print-config" {
+			return
+		}
+		usage, _, _ := strings.Cut(flag.Usage, "\n")
+		fmt.Fprintf(w, "\n# %s (%s)\n", usage, configEnvName(flag.Name))
+		for _, value := range configValues(flag) {
+			fmt.Fprintln(w, strings.TrimSpace(flag.Name+" = "+value))
+		}
+	})
+
+	header := false
+	for _, entry := range c.entries {
+		if strings.HasPrefix(entry.key, configThemePrefix) {
+			if !header {
+				fmt.Fprintln(w, "\n# Theme overrides")
+				header = true
+			}
+			fmt.Fprintf(w, "%s = %s\n", entry.key, entry.value)
+		}
+	}
+
+	fmt.Fprintln(w, "\n# Key bindings, comma separated keys: names like left, space, esc, ctrl+c, f1, comma or single characters")
+	for _, a := range keyActions {
+		fmt.Fprintln(w, strings.TrimSpace(configKeymapPrefix+string(a.action)+" = "+keymap.Keys(a.action)))
+	}
+}
+
+// configValues returns the flag value as it is written in the configuration file,
+// a line for every value of the repeatable flags.
+func configValues(flag *pflag.Flag) []string {
+	slice, ok := flag.Value.(pflag.SliceValue)
+	if !ok {
+		return []string{flag.Value.String()}
+	}
+	if flag.Value.Type() == "stringArray" && len(slice.GetSlice()) > 0 {
+		return slice.GetSlice()
+	}
+	return []string{strings.Join(slice.GetSlice(), ",")}
+}
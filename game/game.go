@@ -24,6 +24,8 @@ import (
 	"log"
 	"math/rand"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/nsf/termbox-go"
 	"golang.org/x/term"
@@ -60,31 +62,62 @@ type Universe interface {
 	Generation() int
 	GameBounds() Bounds
 	Stats() map[int]UniverseStats
+	ForEachAlive(fn func(cell Coord, age int))
+	Restore(generation int, cells map[Coord]int, stats map[int]UniverseStats)
 }
 
+const StatusDuration = 3 * time.Second
+
 type Game struct {
-	Universe Universe
-	Origin   Coord
+	Universe    Universe
+	Origin      Coord
+	Metadata    PatternMetadata
+	Zoom        int
+	census      []string
+	spaceships  string
+	escaped     int
+	activity    *activityMap
+	helpPages   func() [][]string
+	helpPage    int
+	commandLine *CommandLine
+	colorMode   termbox.OutputMode
+	status      string
+	statusUntil time.Time
+	recorder    *CastRecorder
 }
 
 func NewGame(parameters *UsageParameters) Game {
 
-	var u Universe
-	screenWidth, screenHeight, _ := term.GetSize(int(os.Stdout.Fd()))
-	screenWidth -= 2
-	screenHeight -= 2
-	if *parameters.boardType == "infinite" {
-		u = CreateUniverseInfinite(parameters)
-	} else if *parameters.boardType == "boarded" {
-		u = CreateUniverseBoarded(screenWidth, screenHeight, parameters)
-	} else {
+	if *parameters.resume != "" {
+		game, err := restoreGame(*parameters.resume, parameters)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if parameters.gensReached(game.Universe.Generation()) {
+			fmt.Printf("Invalid gens specified: %d, the snapshot is already at generation %d, use a larger value or 0\n",
+				*parameters.gens, game.Universe.Generation())
+			os.Exit(3)
+		}
+		return game
+	}
+
+	placements := collectPlacements(parameters)
+	patterns := make([]Pattern, len(placements))
+	for i, p := range placements {
+		patterns[i] = readFile(&p.path)
+		parameters.applyPatternRule(patterns[i])
+	}
+
+	screenWidth, screenHeight := parameters.screenSize()
+	u, err := createUniverse(*parameters.boardType, screenWidth, screenHeight, parameters)
+	if err != nil {
 		fmt.Printf("Invalid board-type specified: %s\n", *parameters.boardType)
 		os.Exit(3)
 	}
 
 	for i := range screenWidth {
 
-		if *parameters.file != "" {
+		if len(placements) > 0 || *parameters.soup != "" {
 			continue
 		}
 
@@ -100,23 +133,119 @@ func NewGame(parameters *UsageParameters) Game {
 		Origin:   Coord{0, 0},
 	}
 
-	if *parameters.file != "" {
-		matrix := readFile(parameters.file)
-		game.embedMatrix(matrix, screenWidth, screenHeight)
+	if *parameters.soup != "" {
+		offset := Coord{(screenWidth - *parameters.soupSize) / 2, (screenHeight - *parameters.soupSize) / 2}
+		for _, c := range generateSoup(*parameters.soup, *parameters.soupSize) {
+			u.SetAliveCell(c.X+offset.X, c.Y+offset.Y)
+		}
+		game.Metadata.Name = "Soup " + *parameters.soup
+	}
+
+	for i, p := range placements {
+		pattern := patterns[i]
+		game.Metadata = game.Metadata.merge(pattern.Metadata)
+		transformed := p.apply(pattern)
+		if p.offset != nil {
+			game.embedPatternAt(transformed, p.offset.X, p.offset.Y)
+		} else if pattern.Origin != nil {
+			game.embedPatternAt(transformed, pattern.Origin.X, pattern.Origin.Y)
+		} else {
+			game.embedPattern(transformed, screenWidth, screenHeight)
+		}
 	}
 
 	return game
 }
 
-func (game *Game) embedMatrix(source [][]bool, screenWidth int, screenHeight int) {
+// createUniverse creates an empty universe of the board type, the size is used by the boarded universe only.
+func createUniverse(boardType string, width int, height int, parameters *UsageParameters) (Universe, error) {
+	if boardType == "infinite" {
+		return CreateUniverseInfinite(parameters), nil
+	} else if boardType == "boarded" {
+		return CreateUniverseBoarded(width, height, parameters), nil
+	}
+	return nil, fmt.Errorf("unknown board type %q, allowed values are infinite or boarded", boardType)
+}
+
+// SetRule switches the universe to the rule keeping its cells, generation and statistics.
+// Universes keep a copy of the parameters, so the universe is recreated with the new rule.
+func (game *Game) SetRule(rule Rule, parameters *UsageParameters) error {
+	u := game.Universe
+	parameters.rule = rule
+	next, err := cloneUniverse(u, parameters, u.Stats())
+	if err != nil {
+		return err
+	}
+	game.Universe = next
+	return nil
+}
 
-	if len(source) > screenWidth || len(source[0]) > screenHeight && *game.Universe.Parameters().boardType == "boarded" {
+// cloneUniverse copies the cells and the generation of the universe into a new one
+// of the same board type and size with the parameters and statistics.
+func cloneUniverse(u Universe, parameters *UsageParameters, stats map[int]UniverseStats) (Universe, error) {
+	boardType := *u.Parameters().boardType
+	width, height := 0, 0
+	if boardType == "boarded" {
+		bounds := u.GameBounds()
+		width = bounds.BottomRight.X - bounds.TopLeft.X + 1
+		height = bounds.BottomRight.Y - bounds.TopLeft.Y + 1
+	}
+
+	next, err := createUniverse(boardType, width, height, parameters)
+	if err != nil {
+		return nil, err
+	}
+	cells := make(map[Coord]int, u.AliveCount())
+	u.ForEachAlive(func(cell Coord, age int) {
+		cells[cell] = age
+	})
+	next.Restore(u.Generation(), cells, stats)
+	return next, nil
+}
+
+func collectPlacements(parameters *UsageParameters) []placement {
+	var placements []placement
+
+	for _, spec := range *parameters.files {
+		p, err := parsePlacement(spec)
+		if err != nil {
+			fmt.Printf("Invalid file specified: %s\n", err)
+			os.Exit(3)
+		}
+		placements = append(placements, p)
+	}
+
+	if *parameters.scene != "" {
+		scene, err := readScene(*parameters.scene)
+		if err != nil {
+			log.Fatal(err)
+		}
+		placements = append(placements, scene...)
+	}
+
+	return placements
+}
+
+func (game *Game) embedPattern(pattern Pattern, screenWidth int, screenHeight int) {
+
+	width, height := pattern.size()
+	if (width > screenWidth || height > screenHeight) && *game.Universe.Parameters().boardType == "boarded" {
 		log.Fatal("Source matrix is larger than target matrix")
 		return
 	}
 
-	colOffset := (screenWidth - len(source)) / 2
-	rowOffset := (screenHeight - len(source[0])) / 2
+	game.embedPatternAt(pattern, (screenWidth-width)/2, (screenHeight-height)/2)
+}
+
+// embedPatternAt puts the pattern with its top left corner at x, y,
+// sparse patterns are set cell by cell.
+func (game *Game) embedPatternAt(pattern Pattern, x int, y int) {
+	pattern.forEachAlive(func(i int, j int) {
+		game.Universe.SetAliveCell(x+i, y+j)
+	})
+}
+
+func (game *Game) embedMatrixAt(source [][]bool, colOffset int, rowOffset int) {
 
 	for r, row := range source {
 		for c, val := range row {
@@ -127,6 +256,67 @@ func (game *Game) embedMatrix(source [][]bool, screenWidth int, screenHeight int
 	}
 }
 
+// Stamp puts the pattern into the center of the currently visible part of the universe.
+func (game *Game) Stamp(pattern Pattern) {
+	screenWidth, screenHeight, _ := term.GetSize(int(os.Stdout.Fd()))
+	width, height := pattern.size()
+	game.embedPatternAt(
+		pattern,
+		game.Origin.X+(screenWidth-2-width)/2,
+		game.Origin.Y+(screenHeight-2-height)/2)
+}
+
+// Save writes the current generation into the pattern file keeping the metadata
+// of the patterns the universe was composed of.
+func (game *Game) Save(target string, format string) error {
+	if format == "" {
+		format = formatOf(target)
+	}
+	var pattern Pattern
+	if format == FormatMacrocell {
+		pattern = sparsePatternFromUniverse(game.Universe, game.Metadata)
+	} else {
+		pattern = patternFromUniverse(game.Universe, game.Metadata)
+	}
+	pattern.Rule = game.Universe.Parameters().rule.String()
+	return savePattern(target, format, pattern)
+}
+
+// SaveImage writes the current generation inside the viewport as PNG or SVG,
+// with --crop the image is cut to the bounding box of the alive cells.
+func (game *Game) SaveImage(target string, parameters *UsageParameters, viewport Bounds) error {
+	options, err := parameters.imageOptions()
+	if err != nil {
+		return err
+	}
+
+	cells := captureCells(game.Universe)
+	if *parameters.crop {
+		viewport = aliveBounds(cells)
+	}
+	return saveImage(target, cells, viewport, options)
+}
+
+// VisibleBounds returns the part of the universe shown on the screen.
+func (game *Game) VisibleBounds() Bounds {
+	width, height, _ := term.GetSize(int(os.Stdout.Fd()))
+	return Bounds{
+		game.Origin,
+		Coord{game.Origin.X + width - 3, game.Origin.Y + height - 3},
+	}
+}
+
+// SetStatus shows the message in the header for StatusDuration.
+func (game *Game) SetStatus(message string) {
+	game.status = message
+	game.statusUntil = time.Now().Add(StatusDuration)
+}
+
+// ClearStatus shows the pattern title again before the status expires.
+func (game *Game) ClearStatus() {
+	game.statusUntil = time.Time{}
+}
+
 func (game *Game) Pan(x int, y int) {
 	game.Origin.X = game.Origin.X + x
 	game.Origin.Y = game.Origin.Y + y
@@ -146,17 +336,9 @@ func (game *Game) PrintTillResizeComplete() {
 }
 
 func (game *Game) printUniverse() BoardPrintResult {
-	err := termbox.Clear(termbox.ColorDefault, termbox.ColorDefault)
-	if err != nil {
-		panic(err)
-	}
-
 	width, height, _ := term.GetSize(int(os.Stdout.Fd()))
 
-	game.drawBorder(width, height)
-	game.drawCells(width, height)
-	game.drawNavigationArrows(height, width)
-	game.drawInfoText(height, width)
+	screen := game.Render(width, height)
 
 	result := Printed
 
@@ -168,17 +350,48 @@ func (game *Game) printUniverse() BoardPrintResult {
 	}
 
 	if result != BoardResized {
-		err := termbox.Flush()
+		err := screen.Flush()
 		if err != nil {
 			panic(err)
 		}
+		game.record(screen)
 	}
 
 	return result
 }
 
-func (game *Game) drawInfoText(height int, width int) {
+// Render draws the board with the border and the info text into a new screen.
+func (game *Game) Render(width int, height int) *Screen {
+	screen := NewScreen(width, height)
+
+	game.drawBorder(screen, width, height)
+	game.drawCells(screen, width, height)
+	game.drawNavigationArrows(screen, height, width)
+	game.drawInfoText(screen, height, width)
+	game.drawCensus(screen, width, height)
+	game.drawHelp(screen, width, height)
+	game.drawCommandLine(screen, width, height)
+
+	return screen
+}
+
+// record writes the frame into the session recording, recording is stopped on a write error.
+func (game *Game) record(screen *Screen) {
+	if game.recorder == nil {
+		return
+	}
+
+	if err := game.recorder.WriteFrame(screen); err != nil {
+		game.SetStatus("Recording stopped: " + err.Error())
+		game.recorder = nil
+	}
+}
+
+func (game *Game) drawInfoText(screen *Screen, height int, width int) {
 	u := game.Universe
+	theme := u.Parameters().colorTheme()
+	textFg, textBg := theme.Text.colors(game.colorMode)
+	statusFg, statusBg := theme.Status.colors(game.colorMode)
 
 	stats := u.Stats()
 	genStats := stats[u.Generation()]
@@ -186,20 +399,23 @@ func (game *Game) drawInfoText(height int, width int) {
 	generationsText := fmt.Sprintf(" Generation: %d; Population: %d ",
 		u.Generation(),
 		genStats.alive)
-	drawString(
+	screen.DrawString(
 		2,
 		height-1,
 		generationsText,
-		termbox.ColorDefault,
-		termbox.ColorDefault)
+		textFg,
+		textBg)
 
-	originText := fmt.Sprintf(" Origin: x=%d y=%d", game.Origin.X, game.Origin.Y)
-	drawString(
+	originText := fmt.Sprintf(" Origin: x=%d y=%d; Rule: %s ", game.Origin.X, game.Origin.Y, u.Parameters().rule)
+	if game.Zoom > 1 {
+		originText = fmt.Sprintf(" Origin: x=%d y=%d; Zoom: 1:%d; Rule: %s ", game.Origin.X, game.Origin.Y, game.Zoom, u.Parameters().rule)
+	}
+	screen.DrawString(
 		2,
 		0,
 		originText,
-		termbox.ColorDefault,
-		termbox.ColorDefault)
+		textFg,
+		textBg)
 
 	trend := 0.0
 	if genStats.died > 0 {
@@ -208,87 +424,230 @@ func (game *Game) drawInfoText(height int, width int) {
 
 	statsText := fmt.Sprintf(" Born: %d; Died: %d; Born/Died: %f ",
 		genStats.born, genStats.died, trend)
-	drawString(
+	screen.DrawString(
 		width-2-len(statsText),
 		height-1,
 		statsText,
-		termbox.ColorDefault,
-		termbox.ColorDefault)
+		textFg,
+		textBg)
+
+	spaceshipsText := ""
+	if game.spaceships != "" {
+		spaceshipsText = "Spaceships: " + game.spaceships
+	}
+	if game.escaped > 0 {
+		spaceshipsText = strings.TrimPrefix(fmt.Sprintf("%s; Escaped: %d", spaceshipsText, game.escaped), "; ")
+	}
+	if spaceshipsText != "" {
+		left := 3 + len(generationsText)
+		spaceshipsText = truncate(" "+spaceshipsText+" ", width-3-len(statsText)-left)
+		screen.DrawString(
+			left,
+			height-1,
+			spaceshipsText,
+			textFg,
+			textBg)
+	}
+
+	title := game.Metadata.Title()
+	if time.Now().Before(game.statusUntil) {
+		title = game.status
+	}
+	if title != "" {
+		space := width - 8 - len(originText) - len(" Size: width=000 height=000 ")
+		title = truncate(" "+title+" ", space)
+		screen.DrawString(
+			(width-len([]rune(title)))/2,
+			0,
+			title,
+			statusFg,
+			statusBg)
+	}
 
 	bounds := u.GameBounds()
 	sizeText := fmt.Sprintf(" Size: width=%d height=%d ",
 		bounds.BottomRight.X-bounds.TopLeft.X, bounds.BottomRight.Y-bounds.TopLeft.Y)
-	drawString(
+	screen.DrawString(
 		width-2-len(sizeText),
 		0,
 		sizeText,
-		termbox.ColorDefault,
-		termbox.ColorDefault)
+		textFg,
+		textBg)
+}
+
+// ToggleCensus shows the census of the current generation over the board or hides it.
+func (game *Game) ToggleCensus() {
+	if game.census != nil {
+		game.census = nil
+		return
+	}
+	game.census = TakeCensus(game.Universe).Lines()
+}
+
+// drawCensus draws the census table in a box in the top left corner of the board.
+func (game *Game) drawCensus(screen *Screen, width int, height int) {
+	if game.census == nil {
+		return
+	}
+	game.drawOverlay(screen, game.census, width, height, false)
+}
+
+// ToggleHelp shows the pages of the help in the center of the board one after another,
+// the pages are built on every redraw so they show the current settings.
+func (game *Game) ToggleHelp(pages func() [][]string) {
+	game.helpPages = pages
+	game.helpPage = (game.helpPage + 1) % (len(pages()) + 1)
+}
+
+func (game *Game) drawHelp(screen *Screen, width int, height int) {
+	if game.helpPage == 0 {
+		return
+	}
+	pages := game.helpPages()
+	game.drawOverlay(screen, pages[min(game.helpPage, len(pages))-1], width, height, true)
+}
+
+// drawCommandLine draws the ':' prompt over the bottom line of the board.
+func (game *Game) drawCommandLine(screen *Screen, width int, height int) {
+	c := game.commandLine
+	if c == nil || !c.active {
+		return
+	}
+
+	theme := game.Universe.Parameters().colorTheme()
+	textFg, textBg := theme.Text.colors(game.colorMode)
+	hintFg, hintBg := theme.Border.colors(game.colorMode)
+	for x := 1; x < width-1; x++ {
+		screen.SetCell(x, height-1, ' ', textFg, textBg)
+	}
+	prompt := []rune(":" + string(c.text))
+	if len(prompt) > width-4 {
+		prompt = prompt[len(prompt)-(width-4):]
+	}
+	screen.DrawString(1, height-1, string(prompt), textFg, textBg)
+	screen.SetCell(1+len(prompt), height-1, ' ', textFg|termbox.AttrReverse, textBg)
+	if c.hint != "" {
+		screen.DrawString(3+len(prompt), height-1, truncate(c.hint, width-5-len(prompt)), hintFg, hintBg)
+	}
 }
 
-func (game *Game) drawCells(width int, height int) {
+// drawOverlay draws the lines in a box in the top left corner of the board or in its center.
+func (game *Game) drawOverlay(screen *Screen, lines []string, width int, height int, center bool) {
+	boxWidth := 0
+	for _, line := range lines {
+		boxWidth = max(boxWidth, len([]rune(line))+4)
+	}
+	boxWidth = min(boxWidth, width-4)
+	boxHeight := min(len(lines)+2, height-4)
+	if boxWidth < 3 || boxHeight < 3 {
+		return
+	}
+
+	textFg, textBg := game.Universe.Parameters().colorTheme().Text.colors(game.colorMode)
+	box := NewScreen(boxWidth, boxHeight)
+	for i, line := range lines[:boxHeight-2] {
+		box.DrawString(2, i+1, truncate(line, boxWidth-4), textFg, textBg)
+	}
+	game.drawBorder(box, boxWidth, boxHeight)
+
+	left, top := 2, 1
+	if center {
+		left, top = (width-boxWidth)/2, (height-boxHeight)/2
+	}
+	for y := range boxHeight {
+		for x := range boxWidth {
+			c := box.Cell(x, y)
+			screen.SetCell(x+left, y+top, c.Ch, c.Fg, c.Bg)
+		}
+	}
+}
+
+// drawCells draws the cells from the origin, with zoom above 1 every character
+// shows a square block of zoom*zoom cells.
+func (game *Game) drawCells(screen *Screen, width int, height int) {
 	u := game.Universe
+	zoom := max(game.Zoom, 1)
+	theme := u.Parameters().colorTheme()
+	deadColor := theme.Dead.fg.attribute(game.colorMode)
 
 	for i := range width - 2 {
 		for j := range height - 2 {
 			var cell rune
-			isAlive := u.IsAlive(i+game.Origin.X, j+game.Origin.Y)
+			isAlive := game.blockAge(i*zoom+game.Origin.X, j*zoom+game.Origin.Y, zoom)
 			if isAlive > 0 {
 				cell = u.Parameters().symbolAlive
 			} else {
 				cell = ' '
 			}
 
-			var fgColor termbox.Attribute
+			fgColor, bgColor := termbox.ColorDefault, deadColor
+			if isAlive > 0 {
+				fgColor, _ = theme.aliveStyle(isAlive).colors(game.colorMode)
+			}
 
-			if isAlive > 1 {
-				fgColor = termbox.ColorDarkGray
-			} else {
-				fgColor = termbox.ColorGreen
+			if game.activity != nil {
+				if level := game.activity.level(i*zoom+game.Origin.X, j*zoom+game.Origin.Y, zoom); level > 0 {
+					bgColor = heatColor(level, game.colorMode)
+				}
+			}
+			screen.SetCell(i+1, j+1, cell, fgColor, bgColor)
+		}
+	}
+}
+
+// blockAge returns the youngest age of the alive cells in the block, 0 when all are dead.
+func (game *Game) blockAge(x int, y int, size int) int {
+	if size == 1 {
+		return game.Universe.IsAlive(x, y)
+	}
+
+	youngest := 0
+	for i := x; i < x+size; i++ {
+		for j := y; j < y+size; j++ {
+			age := game.Universe.IsAlive(i, j)
+			if age > 0 && (youngest == 0 || age < youngest) {
+				youngest = age
 			}
-			termbox.SetCell(i+1, j+1, cell, fgColor, termbox.ColorDefault)
 		}
 	}
+	return youngest
 }
 
-func (game *Game) drawBorder(width int, height int) {
+func (game *Game) drawBorder(screen *Screen, width int, height int) {
+	borderFg, borderBg := game.Universe.Parameters().colorTheme().Border.colors(game.colorMode)
 	for i := range width {
-		termbox.SetCell(i, 0, '\u2500', termbox.ColorDefault, termbox.ColorDefault)
-		termbox.SetCell(i, height-1, '\u2500', termbox.ColorDefault, termbox.ColorDefault)
+		screen.SetCell(i, 0, '\u2500', borderFg, borderBg)
+		screen.SetCell(i, height-1, '\u2500', borderFg, borderBg)
 	}
 
 	for i := range height {
-		termbox.SetCell(0, i, '\u2502', termbox.ColorDefault, termbox.ColorDefault)
-		termbox.SetCell(width-1, i, '\u2502', termbox.ColorDefault, termbox.ColorDefault)
+		screen.SetCell(0, i, '\u2502', borderFg, borderBg)
+		screen.SetCell(width-1, i, '\u2502', borderFg, borderBg)
 	}
-	termbox.SetCell(0, 0, '\u250C', termbox.ColorDefault, termbox.ColorDefault)
-	termbox.SetCell(width-1, 0, '\u2510', termbox.ColorDefault, termbox.ColorDefault)
-	termbox.SetCell(0, height-1, '\u2514', termbox.ColorDefault, termbox.ColorDefault)
-	termbox.SetCell(width-1, height-1, '\u2518', termbox.ColorDefault, termbox.ColorDefault)
+	screen.SetCell(0, 0, '\u250C', borderFg, borderBg)
+	screen.SetCell(width-1, 0, '\u2510', borderFg, borderBg)
+	screen.SetCell(0, height-1, '\u2514', borderFg, borderBg)
+	screen.SetCell(width-1, height-1, '\u2518', borderFg, borderBg)
 }
 
-func (game *Game) drawNavigationArrows(height int, width int) {
+func (game *Game) drawNavigationArrows(screen *Screen, height int, width int) {
 
 	u := game.Universe
 
 	bounds := u.GameBounds()
 	origin := game.Origin
+	zoom := max(game.Zoom, 1)
+	borderFg, borderBg := u.Parameters().colorTheme().Border.colors(game.colorMode)
 	if bounds.TopLeft.X < origin.X {
-		termbox.SetCell(0, height/2, '\u25C0', termbox.ColorDefault, termbox.ColorDefault)
+		screen.SetCell(0, height/2, '\u25C0', borderFg, borderBg)
 	}
-	if bounds.BottomRight.X > origin.X+width-3 {
-		termbox.SetCell(width-1, height/2, '\u25B6', termbox.ColorDefault, termbox.ColorDefault)
+	if bounds.BottomRight.X > origin.X+(width-2)*zoom-1 {
+		screen.SetCell(width-1, height/2, '\u25B6', borderFg, borderBg)
 	}
 	if bounds.TopLeft.Y < origin.Y {
-		termbox.SetCell(width/2, 0, '\u25B2', termbox.ColorDefault, termbox.ColorDefault)
-	}
-	if bounds.BottomRight.Y > origin.Y+height-3 {
-		termbox.SetCell(width/2, height-1, '\u25BC', termbox.ColorDefault, termbox.ColorDefault)
+		screen.SetCell(width/2, 0, '\u25B2', borderFg, borderBg)
 	}
-}
-
-func drawString(x, y int, s string, fg, bg termbox.Attribute) {
-	for i, ch := range s {
-		termbox.SetCell(x+i, y, ch, fg, bg)
+	if bounds.BottomRight.Y > origin.Y+(height-2)*zoom-1 {
+		screen.SetCell(width/2, height-1, '\u25BC', borderFg, borderBg)
 	}
 }
@@ -20,6 +20,11 @@
 package game
 
 import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/nsf/termbox-go"
@@ -31,10 +36,44 @@ type LifeGameLoop struct {
 }
 
 func (lh *LifeGameLoop) Start(parameters *UsageParameters) {
+	if *parameters.serve != "" {
+		runServer(parameters)
+		return
+	}
+	if *parameters.telnet != "" {
+		runTerminalServer(parameters)
+		return
+	}
+	if *parameters.stdio {
+		runCommandProtocol(parameters)
+		return
+	}
+	if *parameters.analyze != "" {
+		runAnalysis(parameters)
+		return
+	}
+	if *parameters.oscillator != "" {
+		runOscillatorAnalysis(parameters)
+		return
+	}
+	if *parameters.search > 0 {
+		runSearch(parameters)
+		return
+	}
+	if *parameters.script != "" {
+		runScript(parameters)
+		return
+	}
+	if parameters.headless() {
+		runHeadless(parameters)
+		return
+	}
+
 	err := termbox.Init()
 	if err != nil {
 		panic(err)
 	}
+	colorMode := termbox.SetOutputMode(detectColorMode())
 
 	exitMessage := ""
 
@@ -45,8 +84,6 @@ func (lh *LifeGameLoop) Start(parameters *UsageParameters) {
 		}
 	}()
 
-	game := NewGame(parameters)
-
 	keyCh := make(chan termbox.Event, 1)
 
 	go func() {
@@ -56,40 +93,181 @@ func (lh *LifeGameLoop) Start(parameters *UsageParameters) {
 		}
 	}()
 
+	if *parameters.browse != "" {
+		path, ok, err := browseCatalog(parameters, keyCh)
+		if err != nil {
+			exitMessage = err.Error()
+			return
+		}
+		if !ok {
+			return
+		}
+		*parameters.files = append(*parameters.files, path)
+	}
+
+	game := NewGame(parameters)
+	game.colorMode = colorMode
+	if *parameters.heatmap {
+		game.ToggleActivity(*parameters.heatWindow)
+	}
+
+	if *parameters.record != "" {
+		width, height := termbox.Size()
+		recorder, err := NewCastRecorder(*parameters.record, width, height, game.Metadata.Title())
+		if err != nil {
+			exitMessage = err.Error()
+			return
+		}
+		recorder.mode = colorMode
+		game.recorder = recorder
+		defer func() {
+			if err := recorder.Close(); err != nil {
+				exitMessage = err.Error()
+			}
+		}()
+	}
+
+	autosaver := NewAutosaver(parameters, game.Universe.Generation())
+	if autosaver != nil {
+		defer func() {
+			target, err := autosaver.Save(&game, parameters)
+			if err != nil {
+				exitMessage = strings.TrimSpace(exitMessage + "\n" + err.Error())
+			} else {
+				exitMessage = strings.TrimSpace(exitMessage + "\nCheckpoint saved to " + target)
+			}
+		}()
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(sigCh)
+
 	// Main loop (board redraw)
 	tick := time.NewTicker(*parameters.sleep)
 	defer tick.Stop()
 	terminate := false
 	resetTimer := false
 	pause := false
+	count := 0
+	commandLine := &CommandLine{}
+	game.commandLine = commandLine
 	for {
 		select {
+		case sig := <-sigCh:
+			exitMessage = "Terminated by " + sig.String()
+			return
 		case ev := <-keyCh:
-			if ev.Type == termbox.EventKey {
-				if ev.Key == termbox.KeyEsc {
-					return
-				} else if ev.Key == termbox.KeyArrowLeft {
-					game.Pan(-1, 0)
-				} else if ev.Key == termbox.KeyArrowRight {
-					game.Pan(1, 0)
-				} else if ev.Key == termbox.KeyArrowUp {
-					game.Pan(0, -1)
-				} else if ev.Key == termbox.KeyArrowDown {
-					game.Pan(0, 1)
-				} else if ev.Ch == '-' {
-					*parameters.sleep = *parameters.sleep + SpeedIncrement
-					resetTimer = true
-				} else if ev.Ch == '+' {
-					*parameters.sleep = *parameters.sleep - SpeedIncrement
+			if ev.Type != termbox.EventKey {
+				break
+			}
+			if commandLine.active {
+				if line, ok := commandLine.HandleKey(ev); ok {
+					game.SetStatus(ExecuteCommand(&game, parameters, line))
 					resetTimer = true
-					if *parameters.sleep < SpeedIncrement {
-						*parameters.sleep = SpeedIncrement
+				}
+				game.PrintTillResizeComplete()
+				break
+			}
+			action, ok := parameters.keymap.Action(ev)
+			if !ok {
+				if ev.Ch >= '1' && ev.Ch <= '9' || ev.Ch == '0' && count > 0 {
+					count = min(count*10+int(ev.Ch-'0'), MaxCount)
+					game.SetStatus(fmt.Sprintf("Count: %d", count))
+					game.PrintTillResizeComplete()
+				}
+				break
+			}
+			repeat := max(count, 1)
+			if count > 0 {
+				game.ClearStatus()
+				count = 0
+			}
+
+			switch action {
+			case ActionQuit:
+				return
+			case ActionInterrupt:
+				exitMessage = "Interrupted"
+				return
+			case ActionPanLeft:
+				game.Pan(-repeat, 0)
+			case ActionPanRight:
+				game.Pan(repeat, 0)
+			case ActionPanUp:
+				game.Pan(0, -repeat)
+			case ActionPanDown:
+				game.Pan(0, repeat)
+			case ActionPanLeftFast:
+				game.Pan(-repeat*FastPanSteps, 0)
+			case ActionPanRightFast:
+				game.Pan(repeat*FastPanSteps, 0)
+			case ActionPanUpFast:
+				game.Pan(0, -repeat*FastPanSteps)
+			case ActionPanDownFast:
+				game.Pan(0, repeat*FastPanSteps)
+			case ActionSlowDown:
+				*parameters.sleep = *parameters.sleep + SpeedIncrement*time.Duration(repeat)
+				resetTimer = true
+			case ActionSpeedUp:
+				*parameters.sleep = *parameters.sleep - SpeedIncrement*time.Duration(repeat)
+				resetTimer = true
+				if *parameters.sleep < SpeedIncrement {
+					*parameters.sleep = SpeedIncrement
+				}
+			case ActionResetOrigin:
+				game.ResetOrigin(Coord{0, 0})
+			case ActionPause:
+				pause = !pause
+			case ActionSave:
+				target := parameters.saveTarget(game.Universe.Generation())
+				if err := game.Save(target, *parameters.saveFormat); err != nil {
+					game.SetStatus(err.Error())
+				} else {
+					game.SetStatus("Saved " + target)
+				}
+				game.PrintTillResizeComplete()
+			case ActionSnapshot:
+				if err := game.SaveSnapshot(*parameters.snapshot, parameters); err != nil {
+					game.SetStatus(err.Error())
+				} else {
+					game.SetStatus("Saved session to " + *parameters.snapshot)
+				}
+				game.PrintTillResizeComplete()
+			case ActionImage:
+				target := parameters.imageTarget(game.Universe.Generation())
+				if err := game.SaveImage(target, parameters, game.VisibleBounds()); err != nil {
+					game.SetStatus(err.Error())
+				} else {
+					game.SetStatus("Saved " + target)
+				}
+				game.PrintTillResizeComplete()
+			case ActionCensus:
+				game.ToggleCensus()
+				game.PrintTillResizeComplete()
+			case ActionHeatmap:
+				game.ToggleActivity(*parameters.heatWindow)
+				game.PrintTillResizeComplete()
+			case ActionHelp:
+				game.ToggleHelp(func() [][]string {
+					return [][]string{parameters.keymap.HelpLines(), settingsHelpLines(&game, parameters)}
+				})
+				game.PrintTillResizeComplete()
+			case ActionCommand:
+				commandLine.Open()
+				game.PrintTillResizeComplete()
+			case ActionOpen:
+				if path, ok, err := browseCatalog(parameters, keyCh); err != nil {
+					game.SetStatus(err.Error())
+				} else if ok {
+					if pattern, err := loadPattern(path); err != nil {
+						game.SetStatus(err.Error())
+					} else {
+						game.Metadata = game.Metadata.merge(pattern.Metadata)
+						game.Stamp(pattern)
 					}
-				} else if ev.Ch == 'r' {
-					game.ResetOrigin(Coord{0, 0})
-				} else if ev.Key == termbox.KeySpace {
-					pause = !pause
 				}
+				game.PrintTillResizeComplete()
 			}
 		case <-tick.C:
 			game.PrintTillResizeComplete()
@@ -103,10 +281,22 @@ func (lh *LifeGameLoop) Start(parameters *UsageParameters) {
 				terminate = true
 			} else {
 				game.Universe.NextStep()
+				game.recordActivity()
+				if *parameters.escapeDistance > 0 && game.Universe.Generation()%SpaceshipCheckEvery == 0 {
+					game.TrackSpaceships(*parameters.escapeDistance)
+				}
+			}
+
+			if autosaver != nil {
+				if target, err := autosaver.Tick(&game, parameters); err != nil {
+					game.SetStatus(err.Error())
+				} else if target != "" {
+					game.SetStatus("Checkpoint saved to " + target)
+				}
 			}
 		}
 
-		if (game.Universe.Generation() == *parameters.gens && *parameters.gens > 0) || terminate {
+		if parameters.gensReached(game.Universe.Generation()) || terminate {
 			break
 		}
 
@@ -116,3 +306,26 @@ func (lh *LifeGameLoop) Start(parameters *UsageParameters) {
 		}
 	}
 }
+
+// detectColorMode picks the richest colour output the terminal announces.
+func detectColorMode() termbox.OutputMode {
+	switch colorTerm := os.Getenv("COLORTERM"); {
+	case colorTerm == "truecolor" || colorTerm == "24bit":
+		return termbox.OutputRGB
+	case strings.Contains(os.Getenv("TERM"), "256color"):
+		return termbox.Output256
+	default:
+		return termbox.OutputNormal
+	}
+}
+
+// browseCatalog lets the user pick a pattern file, the error of an unreadable catalog is returned
+// so that a running session can show it instead of terminating.
+func browseCatalog(parameters *UsageParameters, keyCh <-chan termbox.Event) (string, bool, error) {
+	browser, err := NewCatalogBrowser(parameters.catalogDir(), parameters.symbolAlive, parameters.colorTheme(), termbox.SetOutputMode(termbox.OutputCurrent))
+	if err != nil {
+		return "", false, err
+	}
+	path, ok := browser.Browse(keyCh)
+	return path, ok, nil
+}
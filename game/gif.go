@@ -0,0 +1,91 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"fmt"
+	"image/gif"
+	"os"
+	"time"
+)
+
+// exportGif renders generations from parameters.gifFrom up to the last
+// generation into an animated GIF. The universe is advanced up to gifFrom first.
+func exportGif(game *Game, parameters *UsageParameters) error {
+	u := game.Universe
+	options, err := parameters.imageOptions()
+	if err != nil {
+		return err
+	}
+
+	for u.Generation() < *parameters.gifFrom && u.AliveCount() > 0 {
+		u.NextStep()
+	}
+
+	var frames [][]cellAge
+	viewport := emptyBounds()
+	for {
+		frames = append(frames, captureCells(u))
+		viewport = viewport.union(u.GameBounds())
+		if u.Generation() >= *parameters.gens || u.AliveCount() == 0 {
+			break
+		}
+		u.NextStep()
+	}
+
+	if *parameters.viewport != AutoViewport {
+		viewport, err = parseViewport(*parameters.viewport)
+		if err != nil {
+			return err
+		}
+	} else if *parameters.crop {
+		viewport = emptyBounds()
+		for _, frame := range frames {
+			viewport = viewport.union(aliveBounds(frame))
+		}
+	}
+	if viewport.isEmpty() {
+		return fmt.Errorf("nothing to render, the population is extinct")
+	}
+
+	delay := int(*parameters.frameDelay / (10 * time.Millisecond))
+	animation := &gif.GIF{}
+	for _, frame := range frames {
+		animation.Image = append(animation.Image, renderPaletted(frame, viewport, options))
+		animation.Delay = append(animation.Delay, delay)
+		animation.Disposal = append(animation.Disposal, gif.DisposalNone)
+	}
+
+	file, err := os.Create(*parameters.gif)
+	if err != nil {
+		return err
+	}
+	err = gif.EncodeAll(file, animation)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Written %d frames of generations %d-%d to %s\n",
+		len(frames), u.Generation()-len(frames)+1, u.Generation(), *parameters.gif)
+	return nil
+}
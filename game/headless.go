@@ -0,0 +1,114 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// runHeadless runs the simulation without the terminal UI and writes the requested exports.
+func runHeadless(parameters *UsageParameters) {
+	if *parameters.gens <= 0 {
+		fmt.Printf("Invalid gens specified: %d, headless run needs a finite number of generations\n", *parameters.gens)
+		os.Exit(3)
+	}
+
+	game := NewGame(parameters)
+
+	if *parameters.gif != "" {
+		if err := exportGif(&game, parameters); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(sigCh)
+
+	u := game.Universe
+	autosaver := NewAutosaver(parameters, u.Generation())
+	interrupted := false
+	for u.Generation() < *parameters.gens && u.AliveCount() > 0 && !interrupted {
+		u.NextStep()
+		if *parameters.escapeDistance > 0 && u.Generation()%SpaceshipCheckEvery == 0 {
+			game.TrackSpaceships(*parameters.escapeDistance)
+		}
+
+		if autosaver != nil {
+			if _, err := autosaver.Tick(&game, parameters); err != nil {
+				log.Fatal(err)
+			}
+		}
+
+		select {
+		case sig := <-sigCh:
+			fmt.Printf("Terminated by %s at generation %d\n", sig, u.Generation())
+			interrupted = true
+		default:
+		}
+	}
+
+	if autosaver != nil {
+		target, err := autosaver.Save(&game, parameters)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Checkpoint saved to %s\n", target)
+	}
+	if interrupted {
+		os.Exit(1)
+	}
+
+	if *parameters.image != "" {
+		viewport := u.GameBounds()
+		if *parameters.viewport != AutoViewport {
+			var err error
+			viewport, err = parseViewport(*parameters.viewport)
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+		if err := game.SaveImage(*parameters.image, parameters, viewport); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Written generation %d to %s\n", u.Generation(), *parameters.image)
+	}
+
+	if game.escaped > 0 {
+		fmt.Printf("Removed %d escaping spaceships\n", game.escaped)
+	}
+
+	if *parameters.census {
+		for _, line := range TakeCensus(u).Lines() {
+			fmt.Println(line)
+		}
+	}
+
+	if *parameters.save != "" {
+		if err := game.Save(*parameters.save, *parameters.saveFormat); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Written generation %d to %s\n", u.Generation(), *parameters.save)
+	}
+}
@@ -22,20 +22,79 @@ package game
 import (
 	"fmt"
 	"os"
+	"runtime"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/spf13/pflag"
+	"golang.org/x/term"
 )
 
-const DefaultSymbolAlive = 'O'
+const (
+	DefaultSymbolAlive  = 'O'
+	DefaultScreenWidth  = 80
+	DefaultScreenHeight = 24
+)
 
 type UsageParameters struct {
 	gens        *int
 	population  *int
 	sleep       *time.Duration
-	file        *string
+	files       *[]string
+	scene       *string
+	browse      *string
+	save        *string
+	saveFormat  *string
+	ruleName    *string
+	rule        Rule
 	symbolAlive rune
+	themeName   *string
+	theme       *Theme
+	keymap      *Keymap
+	config      *string
+	printConfig *bool
 	boardType   *string
+	width       *int
+	height      *int
+	gif         *string
+	gifFrom     *int
+	frameDelay  *time.Duration
+	cellSize    *int
+	palette     *string
+	viewport    *string
+	headlessRun *bool
+	image       *string
+	grid        *bool
+	colors      *string
+	crop        *bool
+	record      *string
+	snapshot    *string
+	resume      *string
+	serve       *string
+	telnet      *string
+	stdio       *bool
+	script      *string
+	census      *bool
+	heatmap     *bool
+	heatWindow  *int
+	soup        *string
+	soupSize    *int
+
+	escapeDistance *int
+	analyze        *string
+	analyzeFormat  *string
+	oscillator     *string
+
+	search       *int
+	seed         *string
+	workers      *int
+	searchOutput *string
+
+	autosaveDir      *string
+	autosaveEvery    *int
+	autosaveInterval *time.Duration
+	autosaveKeep     *int
 }
 
 type LifeHelp struct {
@@ -50,7 +109,13 @@ func (lh *LifeHelp) DefineUsage() *UsageParameters {
 		fmt.Fprintf(os.Stderr, "You can control generations, population density, speed, initial layout file, board type (infinite or boarded).\n")
 		fmt.Fprintf(os.Stderr, "In the ininite board mode you can pan the board with the arrow keys. Also you can use mouse wheel to scroll up and down. To reset origin back pres 'r'.\n\n")
 		fmt.Fprintf(os.Stderr, "To pause simulation press <SPACE>.\n\n")
+		fmt.Fprintf(os.Stderr, "To save the current generation into the --save file press 'w'.\n\n")
+		fmt.Fprintf(os.Stderr, "To save the whole session into the --snapshot file press 's', continue it later with --resume.\n\n")
+		fmt.Fprintf(os.Stderr, "To write the current generation into the --image file press 'p'.\n\n")
+		fmt.Fprintf(os.Stderr, "To open the pattern catalog and stamp a pattern into the center of the view press 'o'.\n\n")
 		fmt.Fprintf(os.Stderr, "To end simulation at any time press <ESC>.\n\n")
+		fmt.Fprintf(os.Stderr, "Press '?' in the game for the keys of all actions, pan with 'hjkl' too, 'HJKL' pans faster and a count typed before an action repeats it.\n")
+		fmt.Fprintf(os.Stderr, "The keys can be rebound by key.<action> lines of the --config file, see --print-config.\n\n")
 		fmt.Fprintf(os.Stderr, "Usage:\n")
 		pflag.PrintDefaults()
 	}
@@ -72,29 +137,422 @@ func (lh *LifeHelp) DefineUsage() *UsageParameters {
 			"sleep",
 			"s", 100*time.Millisecond,
 			"number of seconds to sleep between generations")
-	usageParameters.file =
-		pflag.StringP(
+	usageParameters.files =
+		pflag.StringArrayP(
 			"file",
 			"f",
+			nil,
+			"initial layout file, can be repeated to compose several patterns\n"+
+				"format is path[@x,y][:transform...], e.g. glider.cells@10,20:rot90\n"+
+				"without @x,y the pattern is centered, transforms are rot90, rot180, rot270, flipx, flipy")
+	usageParameters.scene =
+		pflag.String(
+			"scene",
+			"",
+			"scene description file listing one layout file per line in the --file format\n"+
+				"relative paths are resolved against the scene file directory")
+	usageParameters.browse =
+		pflag.StringP(
+			"browse",
+			"b",
+			"",
+			"pick the initial layout from the pattern catalog directory before the game starts")
+	pflag.Lookup("browse").NoOptDefVal = DefaultCatalogDir
+	usageParameters.save =
+		pflag.String(
+			"save",
+			"",
+			"file to save the current generation to when 'w' is pressed, the format is chosen by the extension:\n"+
+				".rle for RLE, .lif or .life for Life 1.06, .mc for macrocell, otherwise plaintext .cells\n"+
+				"life-<generation>.cells is used when not set")
+	usageParameters.saveFormat =
+		pflag.String(
+			"save-format",
 			"",
-			"initial layout file")
+			"format of the saved file overriding the extension, allowed values are cells, rle, life105, life106 or mc")
+	usageParameters.ruleName =
+		pflag.StringP(
+			"rule",
+			"u",
+			"",
+			"Life-like rule in B3/S23 or 23/3 notation\n"+
+				"when not set the rule of the layout file is used, B3/S23 otherwise")
 	symbolAlive :=
 		pflag.StringP("symbol-alive",
 			"a",
 			string(DefaultSymbolAlive),
 			"symbol to represent alive cell on the board\nunicode character can be provided as $'\\u2591'")
+	usageParameters.themeName =
+		pflag.String(
+			"theme",
+			DefaultTheme,
+			"colour theme of the terminal UI, allowed values are "+strings.Join(themeNames(), ", ")+" or a theme file\n"+
+				"RGB colours are shown in truecolor or 256 colours when the terminal supports them, see themes/ for examples")
 	usageParameters.boardType =
 		pflag.StringP("board-type",
 			"t",
 			"infinite",
 			"board type to simulate, allowed values are infinite or boarded")
+	usageParameters.width =
+		pflag.Int(
+			"width",
+			0,
+			"board width, the terminal width is used when not set")
+	usageParameters.height =
+		pflag.Int(
+			"height",
+			0,
+			"board height, the terminal height is used when not set")
+	usageParameters.gif =
+		pflag.String(
+			"gif",
+			"",
+			"run without the terminal UI and render generations from --gif-from up to --gens into an animated GIF file")
+	usageParameters.gifFrom =
+		pflag.Int(
+			"gif-from",
+			0,
+			"first generation rendered into the GIF file")
+	usageParameters.frameDelay =
+		pflag.Duration(
+			"frame-delay",
+			100*time.Millisecond,
+			"delay between GIF frames, rounded down to 10ms")
+	usageParameters.cellSize =
+		pflag.Int(
+			"cell-size",
+			4,
+			"size of a cell in pixels in the exported images")
+	usageParameters.palette =
+		pflag.String(
+			"palette",
+			"classic",
+			"colour palette of the exported images, allowed values are classic, age, green or mono\n"+
+				"classic colours cells by age like the terminal does, age uses a gradient over 8 generations")
+	usageParameters.viewport =
+		pflag.String(
+			"viewport",
+			AutoViewport,
+			"part of the universe rendered into the exported images as x,y,width,height\n"+
+				"auto fits the game bounds of all rendered generations")
+	usageParameters.headlessRun =
+		pflag.Bool(
+			"headless",
+			false,
+			"run --gens generations without the terminal UI, then write the --image and --save files")
+	usageParameters.image =
+		pflag.String(
+			"image",
+			"",
+			"image file to write the current generation to when 'p' is pressed or the headless run ends\n"+
+				".svg extension selects SVG, otherwise PNG is written, life-<generation>.png is used when not set")
+	usageParameters.grid =
+		pflag.Bool(
+			"grid",
+			false,
+			"draw grid lines between cells in the exported images")
+	usageParameters.colors =
+		pflag.String(
+			"colors",
+			"",
+			"colours of the exported images overriding the palette as background,grid,alive[,older alive...], 256 at most\n"+
+				"e.g. #000000,#202020,#00cd00,#808080")
+	usageParameters.crop =
+		pflag.Bool(
+			"crop",
+			false,
+			"crop the exported images to the bounding box of the alive cells instead of the viewport or the visible area")
+	usageParameters.record =
+		pflag.String(
+			"record",
+			"",
+			"record the terminal session into asciinema v2 file, e.g. life.cast")
+	usageParameters.snapshot =
+		pflag.String(
+			"snapshot",
+			"life.snapshot",
+			"file to save the whole session to when 's' is pressed")
+	usageParameters.resume =
+		pflag.String(
+			"resume",
+			"",
+			"continue the session saved in the snapshot file, layout, rule and board type parameters are ignored\n"+
+				"when a directory is given the newest checkpoint in it is used, e.g. --resume checkpoints")
+	usageParameters.serve =
+		pflag.String(
+			"serve",
+			"",
+			"run without the terminal UI and serve the universe to browsers on the address, e.g. :8080")
+	usageParameters.telnet =
+		pflag.String(
+			"telnet",
+			"",
+			"run without the terminal UI and serve the universe to telnet or nc clients on the address, e.g. :2323\n"+
+				"every client pans with arrows, zooms with +/-, resets the origin with r and quits with q")
+	usageParameters.stdio =
+		pflag.Bool(
+			"stdio",
+			false,
+			"run without the terminal UI and execute the commands read from stdin, replies are written to stdout as JSON lines\n"+
+				"the universe starts empty unless layout files are given, send help for the list of the commands")
+	usageParameters.script =
+		pflag.String(
+			"script",
+			"",
+			"run without the terminal UI and execute the commands of the --stdio protocol from the file\n"+
+				"the run stops with exit status 1 at the first failed command or assertion")
+	usageParameters.census =
+		pflag.Bool(
+			"census",
+			false,
+			"print the census of the objects left after the headless run, 'c' shows it in the game")
+	usageParameters.heatmap =
+		pflag.Bool(
+			"heatmap",
+			false,
+			"start with the activity heat map shown, 'a' toggles it in the game\n"+
+				"the background of every cell is coloured by how often it changed over the last --heatmap-window generations")
+	usageParameters.heatWindow =
+		pflag.Int(
+			"heatmap-window",
+			100,
+			"number of generations the activity heat map counts the changes over")
+	usageParameters.search =
+		pflag.Int(
+			"search",
+			0,
+			"run without the terminal UI and search the number of random soups, each one runs until it stabilises\n"+
+				"the objects of all soups are counted into --search-output and the rare ones reported with their soup")
+	usageParameters.seed =
+		pflag.String(
+			"seed",
+			"",
+			"seed of the soup search, soups are named <seed>_<number>, a random one is used when not set")
+	usageParameters.soupSize =
+		pflag.Int(
+			"soup-size",
+			16,
+			"width and height of the random soups")
+	usageParameters.soup =
+		pflag.String(
+			"soup",
+			"",
+			"start with the soup of the search instead of the random board, e.g. --soup 1f2e3d_42")
+	usageParameters.workers =
+		pflag.Int(
+			"workers",
+			runtime.NumCPU(),
+			"number of soups searched in parallel")
+	usageParameters.searchOutput =
+		pflag.String(
+			"search-output",
+			"search.txt",
+			"file to write the object counts of the soup search to")
+	usageParameters.escapeDistance =
+		pflag.Int(
+			"escape-distance",
+			0,
+			"track the spaceships and remove the ones farther than the number of cells from the rest\n"+
+				"of the infinite board and moving away from it, 0 disables the tracking")
+	usageParameters.analyze =
+		pflag.String(
+			"analyze",
+			"",
+			"run without the terminal UI and report the lifespan, final and maximum population, final bounding box\n"+
+				"and escaping gliders of the pattern file or of every pattern in the directory, --gens limits the run")
+	usageParameters.oscillator =
+		pflag.String(
+			"oscillator",
+			"",
+			"run without the terminal UI and report the period, rotor, stator, heat, temperature, volatility\n"+
+				"and the heat map of the oscillator file or of every oscillator in the directory")
+	usageParameters.analyzeFormat =
+		pflag.String(
+			"analyze-format",
+			AnalysisTable,
+			"format of the --analyze and --oscillator reports, allowed values are table or json")
+	usageParameters.autosaveDir =
+		pflag.String(
+			"autosave-dir",
+			"checkpoints",
+			"directory to write the periodic checkpoints to")
+	usageParameters.autosaveEvery =
+		pflag.Int(
+			"autosave-every",
+			0,
+			"write a checkpoint every number of generations, 0 disables it")
+	usageParameters.autosaveInterval =
+		pflag.Duration(
+			"autosave-interval",
+			0,
+			"write a checkpoint every time interval, e.g. 10m, 0 disables it\n"+
+				"with checkpoints enabled a final one is written on exit and on SIGINT, SIGTERM or SIGHUP")
+	usageParameters.autosaveKeep =
+		pflag.Int(
+			"autosave-keep",
+			5,
+			"number of the newest checkpoints to keep")
+	usageParameters.config =
+		pflag.String(
+			"config",
+			"",
+			"configuration file of \"flag = value\" lines with the long flag names and theme.<key> lines overriding the theme\n"+
+				"the default is "+defaultConfigPath()+", every flag can also be set by the "+ConfigEnvPrefix+"<FLAG> environment variable\n"+
+				"e.g. "+configEnvName("board-type")+", the precedence is configuration file < environment < flags")
+	usageParameters.printConfig =
+		pflag.Bool(
+			"print-config",
+			false,
+			"print the effective configuration in the configuration file format and exit")
 	pflag.Parse()
 
+	configPath := *usageParameters.config
+	if !pflag.Lookup("config").Changed {
+		configPath = os.Getenv(configEnvName("config"))
+	}
+	config, err := loadConfig(configPath)
+	if err == nil {
+		err = config.apply(pflag.CommandLine)
+	}
+	if err != nil {
+		fmt.Printf("Invalid config specified: %s\n", err)
+		os.Exit(3)
+	}
+
+	if !slices.Contains([]string{"", FormatCells, FormatRLE, FormatLife105, FormatLife106, FormatMacrocell}, *usageParameters.saveFormat) {
+		fmt.Printf("Invalid save-format specified: %s\n", *usageParameters.saveFormat)
+		os.Exit(3)
+	}
+
+	if *usageParameters.colors != "" {
+		if _, err := (imagePalette{}).parseColors(*usageParameters.colors); err != nil {
+			fmt.Printf("Invalid colors specified: %s\n", err)
+			os.Exit(3)
+		}
+	}
+
+	if *usageParameters.heatWindow <= 0 {
+		fmt.Printf("Invalid heatmap-window specified: %d\n", *usageParameters.heatWindow)
+		os.Exit(3)
+	}
+
+	usageParameters.rule = ConwayRule
+	if *usageParameters.ruleName != "" {
+		rule, err := ParseRule(*usageParameters.ruleName)
+		if err != nil {
+			fmt.Printf("Invalid rule specified: %s\n", err)
+			os.Exit(3)
+		}
+		usageParameters.rule = rule
+	}
+
+	theme, err := loadTheme(*usageParameters.themeName)
+	if err == nil {
+		theme, err = config.applyTheme(theme)
+	}
+	if err != nil {
+		fmt.Printf("Invalid theme specified: %s\n", err)
+		os.Exit(3)
+	}
+	usageParameters.theme = theme
+
+	usageParameters.keymap = DefaultKeymap()
+	if err := config.applyKeymap(usageParameters.keymap); err != nil {
+		fmt.Printf("Invalid config specified: %s\n", err)
+		os.Exit(3)
+	}
+
 	if len(*symbolAlive) > 0 {
 		usageParameters.symbolAlive = []rune(*symbolAlive)[0]
 	} else {
 		usageParameters.symbolAlive = DefaultSymbolAlive
 	}
 
+	if *usageParameters.printConfig {
+		config.print(os.Stdout, pflag.CommandLine, usageParameters.keymap)
+		os.Exit(0)
+	}
+
 	return usageParameters
 }
+
+func (p *UsageParameters) catalogDir() string {
+	if *p.browse != "" {
+		return *p.browse
+	}
+	return DefaultCatalogDir
+}
+
+func (p *UsageParameters) saveTarget(generation int) string {
+	if *p.save != "" {
+		return *p.save
+	}
+	return fmt.Sprintf("life-%d.cells", generation)
+}
+
+// applyPatternRule uses the rule of the layout file unless the rule is given explicitly.
+func (p *UsageParameters) applyPatternRule(pattern Pattern) {
+	if *p.ruleName != "" || pattern.Rule == "" {
+		return
+	}
+
+	rule, err := ParseRule(pattern.Rule)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unsupported rule %q in the layout file, using %s\n", pattern.Rule, p.rule)
+		return
+	}
+	p.rule = rule
+}
+
+// gensReached tells whether the run of --gens generations is over, :step may go past the limit.
+func (p *UsageParameters) gensReached(generation int) bool {
+	return *p.gens > 0 && generation >= *p.gens
+}
+
+func (p *UsageParameters) headless() bool {
+	return *p.headlessRun || *p.gif != ""
+}
+
+// screenSize returns the board size: the --width and --height parameters when set,
+// otherwise the terminal size without the border.
+func (p *UsageParameters) screenSize() (int, int) {
+	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		width, height = DefaultScreenWidth, DefaultScreenHeight
+	}
+	width -= 2
+	height -= 2
+
+	if *p.width > 0 {
+		width = *p.width
+	}
+	if *p.height > 0 {
+		height = *p.height
+	}
+	return width, height
+}
+
+func (p *UsageParameters) imageOptions() (imageOptions, error) {
+	palette, ok := imagePalettes[*p.palette]
+	if !ok {
+		return imageOptions{}, fmt.Errorf("invalid palette %q, allowed values are classic, age, green or mono", *p.palette)
+	}
+	if *p.colors != "" {
+		var err error
+		palette, err = palette.parseColors(*p.colors)
+		if err != nil {
+			return imageOptions{}, err
+		}
+	}
+	if *p.cellSize <= 0 {
+		return imageOptions{}, fmt.Errorf("invalid cell size %d, it must be positive", *p.cellSize)
+	}
+	return imageOptions{cellSize: *p.cellSize, palette: palette, grid: *p.grid}, nil
+}
+
+func (p *UsageParameters) imageTarget(generation int) string {
+	if *p.image != "" {
+		return *p.image
+	}
+	return fmt.Sprintf("life-%d.png", generation)
+}
@@ -0,0 +1,242 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const AutoViewport = "auto"
+
+// imagePalette defines colours of the rendered images, alive cells are
+// coloured by their age: alive[0] is used for new cells, the last colour
+// for every cell older than len(alive) generations.
+type imagePalette struct {
+	background color.RGBA
+	grid       color.RGBA
+	alive      []color.RGBA
+}
+
+var imagePalettes = map[string]imagePalette{
+	// Same colours as drawCells uses in the terminal.
+	"classic": {
+		background: color.RGBA{0, 0, 0, 255},
+		grid:       color.RGBA{40, 40, 40, 255},
+		alive:      []color.RGBA{{0, 205, 0, 255}, {128, 128, 128, 255}},
+	},
+	"age": {
+		background: color.RGBA{0, 0, 0, 255},
+		grid:       color.RGBA{40, 40, 40, 255},
+		alive: []color.RGBA{
+			{255, 255, 255, 255}, {255, 255, 0, 255}, {255, 200, 0, 255}, {255, 140, 0, 255},
+			{255, 70, 0, 255}, {200, 0, 60, 255}, {140, 0, 120, 255}, {70, 0, 160, 255},
+		},
+	},
+	"green": {
+		background: color.RGBA{0, 0, 0, 255},
+		grid:       color.RGBA{40, 40, 40, 255},
+		alive:      []color.RGBA{{0, 205, 0, 255}},
+	},
+	"mono": {
+		background: color.RGBA{255, 255, 255, 255},
+		grid:       color.RGBA{220, 220, 220, 255},
+		alive:      []color.RGBA{{0, 0, 0, 255}},
+	},
+}
+
+// maxImageColors is the size of the paletted image palette including the background and grid.
+const maxImageColors = 256
+
+type imageOptions struct {
+	cellSize int
+	palette  imagePalette
+	grid     bool
+}
+
+// cellAge is an alive cell captured from the universe.
+type cellAge struct {
+	cell Coord
+	age  int
+}
+
+func captureCells(u Universe) []cellAge {
+	cells := make([]cellAge, 0, u.AliveCount())
+	u.ForEachAlive(func(cell Coord, age int) {
+		cells = append(cells, cellAge{cell, age})
+	})
+	return cells
+}
+
+func (p imagePalette) colorPalette() color.Palette {
+	palette := color.Palette{p.background, p.grid}
+	for _, c := range p.alive {
+		palette = append(palette, c)
+	}
+	return palette
+}
+
+// aliveIndex returns the palette index of the cell colour.
+func (p imagePalette) aliveIndex(age int) uint8 {
+	return uint8(2 + min(age, len(p.alive)) - 1)
+}
+
+// imageSize returns the image size in pixels, grid lines take the first pixel row
+// and column of every cell plus the closing line at the right and bottom edges.
+func (o imageOptions) imageSize(viewport Bounds) (int, int) {
+	width := (viewport.BottomRight.X - viewport.TopLeft.X + 1) * o.cellSize
+	height := (viewport.BottomRight.Y - viewport.TopLeft.Y + 1) * o.cellSize
+	if o.grid {
+		width++
+		height++
+	}
+	return width, height
+}
+
+// renderPaletted draws the cells inside the viewport, the viewport corners are inclusive.
+func renderPaletted(cells []cellAge, viewport Bounds, options imageOptions) *image.Paletted {
+	width, height := options.imageSize(viewport)
+	img := image.NewPaletted(image.Rect(0, 0, width, height), options.palette.colorPalette())
+
+	for _, c := range cells {
+		if c.cell.X < viewport.TopLeft.X || c.cell.X > viewport.BottomRight.X ||
+			c.cell.Y < viewport.TopLeft.Y || c.cell.Y > viewport.BottomRight.Y {
+			continue
+		}
+
+		index := options.palette.aliveIndex(c.age)
+		x := (c.cell.X - viewport.TopLeft.X) * options.cellSize
+		y := (c.cell.Y - viewport.TopLeft.Y) * options.cellSize
+		for i := range options.cellSize {
+			for j := range options.cellSize {
+				img.SetColorIndex(x+i, y+j, index)
+			}
+		}
+	}
+
+	if options.grid {
+		for x := 0; x < width; x += options.cellSize {
+			for y := range height {
+				img.SetColorIndex(x, y, 1)
+			}
+		}
+		for y := 0; y < height; y += options.cellSize {
+			for x := range width {
+				img.SetColorIndex(x, y, 1)
+			}
+		}
+	}
+
+	return img
+}
+
+// parseColors overrides the palette colours with a comma separated list
+// of hex colours: background,grid,alive[,older alive...].
+func (p imagePalette) parseColors(colors string) (imagePalette, error) {
+	fields := strings.Split(colors, ",")
+	if len(fields) < 3 {
+		return p, fmt.Errorf("invalid colors %q, expected background,grid,alive[,older alive...]", colors)
+	}
+	if len(fields) > maxImageColors {
+		return p, fmt.Errorf("%d colors given, at most %d fit the image palette", len(fields), maxImageColors)
+	}
+
+	parsed := make([]color.RGBA, len(fields))
+	for i, field := range fields {
+		c, err := parseHexColor(strings.TrimSpace(field))
+		if err != nil {
+			return p, err
+		}
+		parsed[i] = c
+	}
+
+	return imagePalette{background: parsed[0], grid: parsed[1], alive: parsed[2:]}, nil
+}
+
+func parseHexColor(hex string) (color.RGBA, error) {
+	value, err := strconv.ParseUint(strings.TrimPrefix(hex, "#"), 16, 32)
+	if err != nil || len(strings.TrimPrefix(hex, "#")) != 6 {
+		return color.RGBA{}, fmt.Errorf("invalid colour %q, expected #rrggbb", hex)
+	}
+	return color.RGBA{uint8(value >> 16), uint8(value >> 8), uint8(value), 255}, nil
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// aliveBounds returns the bounding box of the alive cells.
+func aliveBounds(cells []cellAge) Bounds {
+	bounds := emptyBounds()
+	for _, c := range cells {
+		bounds = bounds.union(Bounds{c.cell, c.cell})
+	}
+	return bounds
+}
+
+// parseViewport parses x,y,width,height into the viewport bounds.
+func parseViewport(viewport string) (Bounds, error) {
+	fields := strings.Split(viewport, ",")
+	if len(fields) != 4 {
+		return Bounds{}, fmt.Errorf("invalid viewport %q, expected x,y,width,height or %s", viewport, AutoViewport)
+	}
+
+	values := make([]int, 4)
+	for i, field := range fields {
+		value, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return Bounds{}, fmt.Errorf("invalid viewport %q, expected x,y,width,height or %s", viewport, AutoViewport)
+		}
+		values[i] = value
+	}
+	if values[2] <= 0 || values[3] <= 0 {
+		return Bounds{}, fmt.Errorf("invalid viewport %q, width and height must be positive", viewport)
+	}
+
+	return Bounds{
+		Coord{values[0], values[1]},
+		Coord{values[0] + values[2] - 1, values[1] + values[3] - 1},
+	}, nil
+}
+
+func emptyBounds() Bounds {
+	return Bounds{
+		Coord{math.MaxInt, math.MaxInt},
+		Coord{math.MinInt, math.MinInt},
+	}
+}
+
+func (b Bounds) isEmpty() bool {
+	return b.TopLeft.X > b.BottomRight.X || b.TopLeft.Y > b.BottomRight.Y
+}
+
+func (b Bounds) union(other Bounds) Bounds {
+	if other.isEmpty() {
+		return b
+	}
+	return Bounds{
+		Coord{min(b.TopLeft.X, other.TopLeft.X), min(b.TopLeft.Y, other.TopLeft.Y)},
+		Coord{max(b.BottomRight.X, other.BottomRight.X), max(b.BottomRight.Y, other.BottomRight.Y)},
+	}
+}
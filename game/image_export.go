@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// saveImage writes the cells inside the viewport as SVG when the target has
+// .svg extension and as PNG otherwise.
+func saveImage(target string, cells []cellAge, viewport Bounds, options imageOptions) error {
+	if viewport.isEmpty() {
+		return fmt.Errorf("nothing to render, the population is extinct")
+	}
+
+	file, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+
+	if strings.ToLower(filepath.Ext(target)) == ".svg" {
+		err = writeSVG(file, cells, viewport, options)
+	} else {
+		err = png.Encode(file, renderPaletted(cells, viewport, options))
+	}
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// writeSVG writes every row of alive cells as horizontal runs of the same colour.
+func writeSVG(w io.Writer, cells []cellAge, viewport Bounds, options imageOptions) error {
+	bw := bufio.NewWriter(w)
+	width, height := options.imageSize(viewport)
+	size := options.cellSize
+
+	fmt.Fprintf(bw, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\" shape-rendering=\"crispEdges\">\n",
+		width, height, width, height)
+	fmt.Fprintf(bw, "<rect width=\"%d\" height=\"%d\" fill=\"%s\"/>\n", width, height, hexColor(options.palette.background))
+
+	rows := make(map[int]map[int]uint8)
+	for _, c := range cells {
+		if c.cell.X < viewport.TopLeft.X || c.cell.X > viewport.BottomRight.X ||
+			c.cell.Y < viewport.TopLeft.Y || c.cell.Y > viewport.BottomRight.Y {
+			continue
+		}
+		y := c.cell.Y - viewport.TopLeft.Y
+		if rows[y] == nil {
+			rows[y] = make(map[int]uint8)
+		}
+		rows[y][c.cell.X-viewport.TopLeft.X] = options.palette.aliveIndex(c.age)
+	}
+
+	palette := options.palette.colorPalette()
+	columns := viewport.BottomRight.X - viewport.TopLeft.X + 1
+	for y := range viewport.BottomRight.Y - viewport.TopLeft.Y + 1 {
+		row := rows[y]
+		for x := 0; x < columns; {
+			index, alive := row[x]
+			start := x
+			for x < columns && row[x] == index {
+				x++
+			}
+			if alive {
+				fmt.Fprintf(bw, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\"/>\n",
+					start*size, y*size, (x-start)*size, size, hexColor(palette[index].(color.RGBA)))
+			}
+		}
+	}
+
+	if options.grid {
+		fmt.Fprintf(bw, "<path stroke=\"%s\" stroke-width=\"1\" d=\"", hexColor(options.palette.grid))
+		for x := 0; x < width; x += size {
+			fmt.Fprintf(bw, "M%d.5 0V%d", x, height)
+		}
+		for y := 0; y < height; y += size {
+			fmt.Fprintf(bw, "M0 %d.5H%d", y, width)
+		}
+		fmt.Fprintln(bw, "\"/>")
+	}
+
+	fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
@@ -0,0 +1,48 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseColors(t *testing.T) {
+	colors := func(n int) string {
+		return strings.TrimSuffix(strings.Repeat("#102030,", n), ",")
+	}
+
+	palette, err := (imagePalette{}).parseColors(colors(maxImageColors))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := palette.aliveIndex(1000); got != maxImageColors-1 {
+		t.Errorf("got the oldest cell index %d, want %d", got, maxImageColors-1)
+	}
+	if got := len(palette.colorPalette()); got != maxImageColors {
+		t.Errorf("got %d palette colours, want %d", got, maxImageColors)
+	}
+
+	for _, value := range []string{colors(2), colors(maxImageColors + 1), "#000000,#ffffff,green"} {
+		if _, err := (imagePalette{}).parseColors(value); err == nil {
+			t.Errorf("%.40q: expected an error", value)
+		}
+	}
+}
@@ -78,16 +78,21 @@ func (u *InfiniteUniverse) NextStep() {
 	u.generation++
 
 	u.resetBounds()
+	rule := u.parameters.rule
 	for c := range u.board {
 		for _, n := range neighbors {
 			neighbor := Coord{c.X + n.X, c.Y + n.Y}
 			counts[neighbor]++
 		}
+		// Alive cells without neighbours survive only if they are counted.
+		if _, ok := counts[c]; !ok && rule.survival[0] {
+			counts[c] = 0
+		}
 	}
 
 	stats := UniverseStats{}
 	for cell, cnt := range counts {
-		if cnt == 3 || (cnt == 2 && u.board[cell] > 0) {
+		if rule.Next(cnt, u.board[cell] > 0) {
 			if u.board[cell] == 0 {
 				stats.born++
 			}
@@ -160,6 +165,23 @@ func (u *InfiniteUniverse) Stats() map[int]UniverseStats {
 	return u.stats
 }
 
+func (u *InfiniteUniverse) Restore(generation int, cells map[Coord]int, stats map[int]UniverseStats) {
+	u.board = make(map[Coord]int, len(cells))
+	u.resetBounds()
+	for cell, age := range cells {
+		u.board[cell] = age
+		u.setBounds(cell)
+	}
+	u.generation = generation
+	u.stats = stats
+}
+
+func (u *InfiniteUniverse) ForEachAlive(fn func(cell Coord, age int)) {
+	for cell, age := range u.board {
+		fn(cell, age)
+	}
+}
+
 func (u *InfiniteUniverse) setBounds(cell Coord) {
 	if cell.X < u.bounds.TopLeft.X {
 		u.bounds.TopLeft.X = cell.X
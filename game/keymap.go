@@ -0,0 +1,244 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nsf/termbox-go"
+)
+
+const (
+	// FastPanSteps is the distance of the fast pan actions in pan steps.
+	FastPanSteps = 10
+	// MaxCount limits the count typed before an action.
+	MaxCount = 9999
+)
+
+type Action string
+
+const (
+	ActionPanLeft      Action = "pan-left"
+	ActionPanRight     Action = "pan-right"
+	ActionPanUp        Action = "pan-up"
+	ActionPanDown      Action = "pan-down"
+	ActionPanLeftFast  Action = "pan-left-fast"
+	ActionPanRightFast Action = "pan-right-fast"
+	ActionPanUpFast    Action = "pan-up-fast"
+	ActionPanDownFast  Action = "pan-down-fast"
+	ActionResetOrigin  Action = "reset-origin"
+	ActionSpeedUp      Action = "speed-up"
+	ActionSlowDown     Action = "slow-down"
+	ActionPause        Action = "pause"
+	ActionSave         Action = "save"
+	ActionSnapshot     Action = "snapshot"
+	ActionImage        Action = "image"
+	ActionCensus       Action = "census"
+	ActionHeatmap      Action = "heatmap"
+	ActionOpen         Action = "open"
+	ActionHelp         Action = "help"
+	ActionCommand      Action = "command"
+	ActionQuit         Action = "quit"
+	ActionInterrupt    Action = "interrupt"
+)
+
+type keyAction struct {
+	action      Action
+	keys        string
+	description string
+}
+
+// keyActions are the actions in the order of the help overlay with their default keys.
+var keyActions = []keyAction{
+	{ActionPanLeft, "left, h", "pan left"},
+	{ActionPanRight, "right, l", "pan right"},
+	{ActionPanUp, "up, k", "pan up"},
+	{ActionPanDown, "down, j", "pan down"},
+	{ActionPanLeftFast, "H", fmt.Sprintf("pan left by %d steps", FastPanSteps)},
+	{ActionPanRightFast, "L", fmt.Sprintf("pan right by %d steps", FastPanSteps)},
+	{ActionPanUpFast, "K", fmt.Sprintf("pan up by %d steps", FastPanSteps)},
+	{ActionPanDownFast, "J", fmt.Sprintf("pan down by %d steps", FastPanSteps)},
+	{ActionResetOrigin, "r", "reset the origin"},
+	{ActionSpeedUp, "+", "speed up"},
+	{ActionSlowDown, "-", "slow down"},
+	{ActionPause, "space", "pause or resume"},
+	{ActionSave, "w", "save the current generation"},
+	{ActionSnapshot, "s", "save the session"},
+	{ActionImage, "p", "write the view as an image"},
+	{ActionCensus, "c", "show or hide the census"},
+	{ActionHeatmap, "a", "show or hide the activity heat map"},
+	{ActionOpen, "o", "open the pattern catalog"},
+	{ActionHelp, "?", "show the next help page or hide the help"},
+	{ActionCommand, ":", "enter a command, <TAB> completes"},
+	{ActionQuit, "esc, q", "quit"},
+	{ActionInterrupt, "ctrl+c", "interrupt"},
+}
+
+var keyNames = map[string]termbox.Key{
+	"esc":       termbox.KeyEsc,
+	"space":     termbox.KeySpace,
+	"enter":     termbox.KeyEnter,
+	"tab":       termbox.KeyTab,
+	"backspace": termbox.KeyBackspace2,
+	"insert":    termbox.KeyInsert,
+	"delete":    termbox.KeyDelete,
+	"home":      termbox.KeyHome,
+	"end":       termbox.KeyEnd,
+	"pgup":      termbox.KeyPgup,
+	"pgdn":      termbox.KeyPgdn,
+	"left":      termbox.KeyArrowLeft,
+	"right":     termbox.KeyArrowRight,
+	"up":        termbox.KeyArrowUp,
+	"down":      termbox.KeyArrowDown,
+}
+
+// keyRunes are the names of the characters that can't be written in the key lists.
+var keyRunes = map[string]rune{
+	"comma": ',',
+	"equal": '=',
+	"hash":  '#',
+}
+
+// keyStroke is either a special key or a character.
+type keyStroke struct {
+	key termbox.Key
+	ch  rune
+}
+
+// Keymap binds the keys to the actions of the game loop.
+type Keymap struct {
+	actions map[keyStroke]Action
+	keys    map[Action][]string
+}
+
+func DefaultKeymap() *Keymap {
+	k := &Keymap{actions: make(map[keyStroke]Action), keys: make(map[Action][]string)}
+	for _, a := range keyActions {
+		if err := k.Bind(a.action, a.keys); err != nil {
+			panic(err)
+		}
+	}
+	return k
+}
+
+// Bind replaces the keys of the action with the comma separated list, e.g. "left, h".
+// The keys are taken from the actions they were bound to, an empty list unbinds the action.
+func (k *Keymap) Bind(action Action, keys string) error {
+	if !slices.ContainsFunc(keyActions, func(a keyAction) bool { return a.action == action }) {
+		return fmt.Errorf("unknown action %q", action)
+	}
+
+	var names []string
+	var strokes []keyStroke
+	for _, name := range strings.Split(keys, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		stroke, err := parseKey(name)
+		if err != nil {
+			return err
+		}
+		names = append(names, name)
+		strokes = append(strokes, stroke)
+	}
+
+	for stroke, bound := range k.actions {
+		if bound == action {
+			delete(k.actions, stroke)
+		}
+	}
+	for _, stroke := range strokes {
+		if previous, ok := k.actions[stroke]; ok {
+			k.keys[previous] = slices.DeleteFunc(k.keys[previous], func(name string) bool {
+				other, _ := parseKey(name)
+				return other == stroke
+			})
+		}
+		k.actions[stroke] = action
+	}
+	k.keys[action] = names
+	return nil
+}
+
+// parseKey parses a key name, ctrl+<letter>, f1-f12 or a single character.
+func parseKey(name string) (keyStroke, error) {
+	lower := strings.ToLower(name)
+	if key, ok := keyNames[lower]; ok {
+		return keyStroke{key: key}, nil
+	}
+	if ch, ok := keyRunes[lower]; ok {
+		return keyStroke{ch: ch}, nil
+	}
+	if letter, ok := strings.CutPrefix(lower, "ctrl+"); ok && len(letter) == 1 && letter[0] >= 'a' && letter[0] <= 'z' {
+		return keyStroke{key: termbox.KeyCtrlA + termbox.Key(letter[0]-'a')}, nil
+	}
+	var function int
+	if _, err := fmt.Sscanf(lower, "f%d", &function); err == nil && function >= 1 && function <= 12 && lower == fmt.Sprintf("f%d", function) {
+		return keyStroke{key: termbox.KeyF1 - termbox.Key(function-1)}, nil
+	}
+	if utf8.RuneCountInString(name) == 1 {
+		ch, _ := utf8.DecodeRuneInString(name)
+		if ch == ' ' {
+			return keyStroke{key: termbox.KeySpace}, nil
+		}
+		return keyStroke{ch: ch}, nil
+	}
+	return keyStroke{}, fmt.Errorf("unknown key %q", name)
+}
+
+// Action returns the action bound to the key of the event.
+func (k *Keymap) Action(ev termbox.Event) (Action, bool) {
+	stroke := keyStroke{ch: ev.Ch}
+	if ev.Ch == 0 {
+		stroke = keyStroke{key: ev.Key}
+	}
+	action, ok := k.actions[stroke]
+	return action, ok
+}
+
+// Keys returns the comma separated keys of the action.
+func (k *Keymap) Keys(action Action) string {
+	return strings.Join(k.keys[action], ", ")
+}
+
+// HelpLines lists the actions with their keys for the help overlay.
+func (k *Keymap) HelpLines() []string {
+	width := 0
+	for _, a := range keyActions {
+		width = max(width, len(k.Keys(a.action)))
+	}
+
+	lines := []string{"Keys, type a count before an action to repeat it", ""}
+	if keys := k.Keys(ActionHelp); keys != "" {
+		lines[0] = fmt.Sprintf("Keys, type a count before an action to repeat it, %s shows the settings", keys)
+	}
+	for _, a := range keyActions {
+		keys := k.Keys(a.action)
+		if keys == "" {
+			keys = "-"
+		}
+		lines = append(lines, fmt.Sprintf("%-*s  %s", width, keys, a.description))
+	}
+	return lines
+}
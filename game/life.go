@@ -0,0 +1,186 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	life105Header = "#Life 1.05"
+	life106Header = "#Life 1.06"
+)
+
+// parseLife reads both Life 1.05 and Life 1.06 formats, the version is taken
+// from the header line. Coordinates are absolute, so the pattern Origin is set.
+func parseLife(r io.Reader, limit patternLimit) (Pattern, error) {
+
+	var pattern Pattern
+	var cells []Coord
+	version := ""
+	block := Coord{}
+	row := 0
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "#Life") {
+			version = line
+			continue
+		}
+		if line[0] == '#' {
+			if len(line) < 2 {
+				continue
+			}
+			switch line[1] {
+			case 'D', 'C':
+				pattern.Metadata.parseCellsComment(line[2:])
+			case 'N':
+				pattern.Rule = ConwayRule.String()
+			case 'R':
+				pattern.Rule = strings.TrimSpace(line[2:])
+			case 'P':
+				x, y, err := parseLifeCoord(line[2:])
+				if err != nil {
+					return pattern, err
+				}
+				block = Coord{x, y}
+				row = 0
+			}
+			continue
+		}
+
+		if version == life106Header || version == "" && strings.ContainsAny(line, "0123456789") {
+			x, y, err := parseLifeCoord(line)
+			if err != nil {
+				return pattern, err
+			}
+			cells = append(cells, Coord{x, y})
+			continue
+		}
+
+		for i, ch := range line {
+			if ch == '*' || ch == 'O' {
+				cells = append(cells, Coord{block.X + i, block.Y + row})
+			} else if ch != '.' {
+				return pattern, fmt.Errorf("unexpected symbol %q in Life 1.05 data", ch)
+			}
+		}
+		row++
+	}
+
+	if err := scanner.Err(); err != nil {
+		return pattern, err
+	}
+	if len(cells) > 0 {
+		bounds := coordBounds(cells)
+		width := bounds.BottomRight.X - bounds.TopLeft.X + 1
+		height := bounds.BottomRight.Y - bounds.TopLeft.Y + 1
+		if err := limit.dense().check(width, height); err != nil {
+			return pattern, err
+		}
+	}
+
+	pattern.setCells(cells)
+	return pattern, nil
+}
+
+func parseLifeCoord(line string) (int, int, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("invalid coordinates %q", line)
+	}
+	x, errX := strconv.Atoi(fields[0])
+	y, errY := strconv.Atoi(fields[1])
+	if errX != nil || errY != nil {
+		return 0, 0, fmt.Errorf("invalid coordinates %q", line)
+	}
+	return x, y, nil
+}
+
+func writeLife106(w io.Writer, pattern Pattern) error {
+
+	bw := bufio.NewWriter(w)
+	origin := pattern.origin()
+	width, height := matrixSize(pattern.Cells)
+
+	fmt.Fprintln(bw, life106Header)
+	for y := range height {
+		for x := range width {
+			if pattern.Cells[x][y] {
+				fmt.Fprintf(bw, "%d %d\n", origin.X+x, origin.Y+y)
+			}
+		}
+	}
+
+	return bw.Flush()
+}
+
+// writeLife105 writes the whole pattern as a single #P block,
+// metadata goes to the #D description lines.
+func writeLife105(w io.Writer, pattern Pattern) error {
+
+	bw := bufio.NewWriter(w)
+	origin := pattern.origin()
+	width, height := matrixSize(pattern.Cells)
+
+	fmt.Fprintln(bw, life105Header)
+	if pattern.Metadata.Name != "" {
+		fmt.Fprintf(bw, "#D Name: %s\n", pattern.Metadata.Name)
+	}
+	if pattern.Metadata.Author != "" {
+		fmt.Fprintf(bw, "#D Author: %s\n", pattern.Metadata.Author)
+	}
+	for _, comment := range pattern.Metadata.Comments {
+		fmt.Fprintf(bw, "#D %s\n", comment)
+	}
+	if rule, err := ParseRule(pattern.rule()); err == nil && rule != ConwayRule {
+		fmt.Fprintf(bw, "#R %s\n", rule.survivalBirthString())
+	} else {
+		fmt.Fprintln(bw, "#N")
+	}
+	fmt.Fprintf(bw, "#P %d %d\n", origin.X, origin.Y)
+
+	for y := range height {
+		line := make([]byte, width)
+		for x := range width {
+			if pattern.Cells[x][y] {
+				line[x] = '*'
+			} else {
+				line[x] = '.'
+			}
+		}
+		trimmed := strings.TrimRight(string(line), ".")
+		if trimmed == "" {
+			trimmed = "."
+		}
+		fmt.Fprintln(bw, trimmed)
+	}
+
+	return bw.Flush()
+}
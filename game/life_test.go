@@ -0,0 +1,141 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseLife105Blocks(t *testing.T) {
+	data := `#Life 1.05
+#D Name: Two blocks
+#D Two separate blocks.
+#N
+#P -2 -1
+**
+**
+#P 3 2
+.*
+*.
+`
+	pattern, err := parseLife(strings.NewReader(data), defaultPatternLimit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// #P coordinates are absolute, so the second block lands at x=3 y=2.
+	want := []string{
+		"oo.....",
+		"oo.....",
+		".......",
+		"......o",
+		".....o.",
+	}
+	if got := rows(pattern.Cells); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if pattern.Origin == nil || *pattern.Origin != (Coord{-2, -1}) {
+		t.Errorf("got origin %v, want {-2 -1}", pattern.Origin)
+	}
+	if pattern.Metadata.Name != "Two blocks" || !reflect.DeepEqual(pattern.Metadata.Comments, []string{"Two separate blocks."}) {
+		t.Errorf("unexpected metadata %+v", pattern.Metadata)
+	}
+	if pattern.Rule != ConwayRule.String() {
+		t.Errorf("got rule %q, want %q", pattern.Rule, ConwayRule.String())
+	}
+}
+
+func TestParseLife105Rule(t *testing.T) {
+	pattern, err := parseLife(strings.NewReader("#Life 1.05\n#R 23/36\n#P 0 0\n*\n"), defaultPatternLimit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rule, err := ParseRule(pattern.Rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, _ := ParseRule("B36/S23"); rule != want {
+		t.Errorf("got rule %v, want %v", rule, want)
+	}
+}
+
+func TestParseLife106(t *testing.T) {
+	data := `#Life 1.06
+10 -5
+11 -4
+9 -3
+10 -3
+11 -3
+`
+	pattern, err := parseLife(strings.NewReader(data), defaultPatternLimit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{".o.", "..o", "ooo"}
+	if got := rows(pattern.Cells); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if pattern.Origin == nil || *pattern.Origin != (Coord{9, -5}) {
+		t.Errorf("got origin %v, want {9 -5}", pattern.Origin)
+	}
+}
+
+func TestParseLifeErrors(t *testing.T) {
+	for _, data := range []string{
+		"#Life 1.06\n1 2 3\n",
+		"#Life 1.06\n1 a\n",
+		"#Life 1.05\n#P 0\n*\n",
+		"#Life 1.05\n#P 0 0\n*x*\n",
+	} {
+		if _, err := parseLife(strings.NewReader(data), defaultPatternLimit); err == nil {
+			t.Errorf("%q: expected an error", data)
+		}
+	}
+}
+
+func TestLifeRoundTrip(t *testing.T) {
+	for _, format := range []string{FormatLife105, FormatLife106} {
+		t.Run(format, func(t *testing.T) {
+			pattern := Pattern{
+				Cells:  fromRows("o..o", "....", ".oo."),
+				Origin: &Coord{-7, 12},
+			}
+
+			var buf bytes.Buffer
+			if err := writePattern(&buf, format, pattern); err != nil {
+				t.Fatal(err)
+			}
+			got, err := parsePattern(&buf, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(rows(got.Cells), rows(pattern.Cells)) {
+				t.Errorf("got %q, want %q", rows(got.Cells), rows(pattern.Cells))
+			}
+			if got.Origin == nil || *got.Origin != *pattern.Origin {
+				t.Errorf("got origin %v, want %v", got.Origin, *pattern.Origin)
+			}
+		})
+	}
+}
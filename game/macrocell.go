@@ -0,0 +1,309 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	macrocellHeader    = "[M2] (go-life)"
+	macrocellLeafLevel = 3
+	macrocellLeafSize  = 1 << macrocellLeafLevel
+	// macrocellMaxLevel keeps the coordinates of the root node in 32 bits.
+	macrocellMaxLevel = 32
+	// maxSparseCells limits the alive cells a pattern expands to, the same node
+	// may be referred to many times.
+	maxSparseCells = 1 << 22
+)
+
+// macrocellNode is a quadtree node. Two-state files store 8x8 leaves
+// as text, multi-state files store level 1 nodes with cell states.
+type macrocellNode struct {
+	level    int
+	children [4]int
+	leaf     []Coord
+}
+
+// parseMacrocell reads Golly macrocell format, both two-state and multi-state.
+// Any non-zero state is an alive cell. The root node is centered on
+// the universe origin as Golly does. The pattern is kept sparse.
+func parseMacrocell(r io.Reader, limit patternLimit) (Pattern, error) {
+
+	var pattern Pattern
+	nodes := []macrocellNode{{}}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '[' {
+			continue
+		}
+
+		if line[0] == '#' {
+			if len(line) < 2 {
+				continue
+			}
+			switch line[1] {
+			case 'C', 'D':
+				pattern.Metadata.parseCellsComment(line[2:])
+			case 'R':
+				pattern.Rule = strings.TrimSpace(line[2:])
+			}
+			continue
+		}
+
+		if line[0] == '.' || line[0] == '*' || line[0] == '$' {
+			nodes = append(nodes, parseMacrocellLeaf(line))
+			continue
+		}
+
+		node, err := parseMacrocellNode(line, len(nodes))
+		if err != nil {
+			return pattern, err
+		}
+		nodes = append(nodes, node)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return pattern, err
+	}
+	if len(nodes) == 1 {
+		return pattern, nil
+	}
+
+	root := len(nodes) - 1
+	half := 1 << (nodes[root].level - 1)
+	var cells []Coord
+	if !expandMacrocell(nodes, root, Coord{-half, -half}, &cells) {
+		return pattern, fmt.Errorf("macrocell pattern has more than %d alive cells", maxSparseCells)
+	}
+	pattern.setSparse(cells)
+	if err := limit.check(pattern.size()); err != nil {
+		return pattern, err
+	}
+
+	return pattern, nil
+}
+
+func parseMacrocellLeaf(line string) macrocellNode {
+	node := macrocellNode{level: macrocellLeafLevel}
+	x, y := 0, 0
+	for _, ch := range line {
+		switch ch {
+		case '$':
+			x = 0
+			y++
+		case '*':
+			node.leaf = append(node.leaf, Coord{x, y})
+			x++
+		default:
+			x++
+		}
+	}
+	return node
+}
+
+func parseMacrocellNode(line string, index int) (macrocellNode, error) {
+	var node macrocellNode
+
+	fields := strings.Fields(line)
+	if len(fields) != 5 {
+		return node, fmt.Errorf("invalid macrocell node %d: %q", index, line)
+	}
+	values := make([]int, 5)
+	for i, field := range fields {
+		value, err := strconv.Atoi(field)
+		if err != nil {
+			return node, fmt.Errorf("invalid macrocell node %d: %q", index, line)
+		}
+		values[i] = value
+	}
+
+	node.level = values[0]
+	if node.level < 1 || node.level > macrocellMaxLevel {
+		return node, fmt.Errorf("invalid macrocell node level %d: %q", index, line)
+	}
+	copy(node.children[:], values[1:])
+	if node.level == 1 {
+		// Multi-state leaf, children are the cell states of nw, ne, sw and se cells.
+		quadrants := []Coord{{0, 0}, {1, 0}, {0, 1}, {1, 1}}
+		for i, state := range node.children {
+			if state != 0 {
+				node.leaf = append(node.leaf, quadrants[i])
+			}
+		}
+		return node, nil
+	}
+	for _, child := range node.children {
+		if child >= index {
+			return node, fmt.Errorf("macrocell node %d refers to unknown node %d", index, child)
+		}
+	}
+
+	return node, nil
+}
+
+// expandMacrocell appends the alive cells of the node, false is returned
+// when there are more than maxSparseCells of them.
+func expandMacrocell(nodes []macrocellNode, index int, topLeft Coord, cells *[]Coord) bool {
+	if index == 0 {
+		return true
+	}
+
+	node := nodes[index]
+	if node.leaf != nil || node.level <= macrocellLeafLevel && node.children == [4]int{} {
+		if len(*cells)+len(node.leaf) > maxSparseCells {
+			return false
+		}
+		for _, c := range node.leaf {
+			*cells = append(*cells, Coord{topLeft.X + c.X, topLeft.Y + c.Y})
+		}
+		return true
+	}
+
+	half := 1 << (node.level - 1)
+	return expandMacrocell(nodes, node.children[0], topLeft, cells) &&
+		expandMacrocell(nodes, node.children[1], Coord{topLeft.X + half, topLeft.Y}, cells) &&
+		expandMacrocell(nodes, node.children[2], Coord{topLeft.X, topLeft.Y + half}, cells) &&
+		expandMacrocell(nodes, node.children[3], Coord{topLeft.X + half, topLeft.Y + half}, cells)
+}
+
+// macrocellWriter builds the quadtree bottom up, identical nodes are written once.
+type macrocellWriter struct {
+	lines []string
+	index map[string]int
+}
+
+func (w *macrocellWriter) add(line string) int {
+	if id, ok := w.index[line]; ok {
+		return id
+	}
+	w.lines = append(w.lines, line)
+	w.index[line] = len(w.lines)
+	return len(w.lines)
+}
+
+// writeMacrocell writes the two-state macrocell format with 8x8 leaves,
+// pattern coordinates are kept relative to the universe origin.
+func writeMacrocell(w io.Writer, pattern Pattern) error {
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, macrocellHeader)
+	fmt.Fprintf(bw, "#R %s\n", pattern.rule())
+	if pattern.Metadata.Name != "" {
+		fmt.Fprintf(bw, "#C Name: %s\n", pattern.Metadata.Name)
+	}
+	if pattern.Metadata.Author != "" {
+		fmt.Fprintf(bw, "#C Author: %s\n", pattern.Metadata.Author)
+	}
+	for _, comment := range pattern.Metadata.Comments {
+		fmt.Fprintf(bw, "#C %s\n", comment)
+	}
+
+	origin := pattern.origin()
+	leaves := make(map[Coord]*[macrocellLeafSize][macrocellLeafSize]bool)
+	extent := 0
+	pattern.forEachAlive(func(x int, y int) {
+		c := Coord{origin.X + x, origin.Y + y}
+		extent = max(extent, -c.X, -c.Y, c.X+1, c.Y+1)
+		block := Coord{c.X >> macrocellLeafLevel, c.Y >> macrocellLeafLevel}
+		if leaves[block] == nil {
+			leaves[block] = new([macrocellLeafSize][macrocellLeafSize]bool)
+		}
+		leaves[block][c.Y&(macrocellLeafSize-1)][c.X&(macrocellLeafSize-1)] = true
+	})
+
+	if len(leaves) > 0 {
+		mw := &macrocellWriter{index: make(map[string]int)}
+
+		// The root is the smallest node centered on the origin covering every cell.
+		rootLevel := macrocellLeafLevel + 1
+		for 1<<(rootLevel-1) < extent {
+			rootLevel++
+		}
+
+		level := make(map[Coord]int, len(leaves))
+		for _, block := range sortedCoords(leaves) {
+			level[block] = mw.add(macrocellLeafLine(leaves[block]))
+		}
+		for l := macrocellLeafLevel + 1; l < rootLevel; l++ {
+			level = mw.addLevel(l, level)
+		}
+		mw.add(fmt.Sprintf("%d %d %d %d %d", rootLevel,
+			level[Coord{-1, -1}], level[Coord{0, -1}], level[Coord{-1, 0}], level[Coord{0, 0}]))
+
+		for _, line := range mw.lines {
+			fmt.Fprintln(bw, line)
+		}
+	}
+
+	return bw.Flush()
+}
+
+// addLevel combines the nodes of the previous level into their parents.
+func (w *macrocellWriter) addLevel(level int, children map[Coord]int) map[Coord]int {
+	quadrants := make(map[Coord]*[4]int)
+	for c, id := range children {
+		parent := Coord{c.X >> 1, c.Y >> 1}
+		if quadrants[parent] == nil {
+			quadrants[parent] = new([4]int)
+		}
+		quadrants[parent][(c.Y&1)*2+c.X&1] = id
+	}
+
+	parents := make(map[Coord]int, len(quadrants))
+	for _, c := range sortedCoords(quadrants) {
+		q := quadrants[c]
+		parents[c] = w.add(fmt.Sprintf("%d %d %d %d %d", level, q[0], q[1], q[2], q[3]))
+	}
+	return parents
+}
+
+func macrocellLeafLine(leaf *[macrocellLeafSize][macrocellLeafSize]bool) string {
+	rows := make([]string, macrocellLeafSize)
+	for y, row := range leaf {
+		line := make([]byte, macrocellLeafSize)
+		for x, alive := range row {
+			if alive {
+				line[x] = '*'
+			} else {
+				line[x] = '.'
+			}
+		}
+		rows[y] = strings.TrimRight(string(line), ".") + "$"
+	}
+	return strings.TrimRight(strings.Join(rows, ""), "$") + "$"
+}
+
+// sortedCoords returns the map keys row by row to keep the output stable.
+func sortedCoords[V any](m map[Coord]V) []Coord {
+	return slices.SortedFunc(maps.Keys(m), func(a, b Coord) int {
+		return cmp.Or(cmp.Compare(a.Y, b.Y), cmp.Compare(a.X, b.X))
+	})
+}
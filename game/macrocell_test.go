@@ -0,0 +1,189 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// patternRows shows the pattern as rows, sparse ones included.
+func patternRows(pattern Pattern) []string {
+	width, height := pattern.size()
+	cells := newMatrix(width, height)
+	pattern.forEachAlive(func(x int, y int) {
+		cells[x][y] = true
+	})
+	return rows(cells)
+}
+
+func TestParseMacrocell(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		want   []string
+		origin Coord
+	}{
+		{
+			"two-state glider",
+			"[M2] (golly 2.0)\n#R B3/S23\n.*$..*$***$\n4 0 0 1 0\n",
+			[]string{".o.", "..o", "ooo"},
+			Coord{-8, 0},
+		},
+		{
+			"multi-state leaves",
+			"[M2] (golly 2.0)\n#R B3/S23\n1 0 1 2 0\n",
+			[]string{".o", "o."},
+			Coord{-1, -1},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pattern, err := parseMacrocell(strings.NewReader(test.data), defaultPatternLimit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := patternRows(pattern); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+			if pattern.Origin == nil || *pattern.Origin != test.origin {
+				t.Errorf("got origin %v, want %v", pattern.Origin, test.origin)
+			}
+			if pattern.Rule != "B3/S23" {
+				t.Errorf("got rule %q, want B3/S23", pattern.Rule)
+			}
+		})
+	}
+}
+
+func TestParseMacrocellErrors(t *testing.T) {
+	for _, data := range []string{
+		"[M2]\n4 1 0 0 0\n",
+		"[M2]\n.*$\n4 0 0 1\n",
+		"[M2]\n.*$\n4 0 0 x 0\n",
+		"[M2]\n.*$\n99 0 0 1 0\n",
+	} {
+		if _, err := parseMacrocell(strings.NewReader(data), defaultPatternLimit); err == nil {
+			t.Errorf("%q: expected an error", data)
+		}
+	}
+}
+
+func TestMacrocellRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		cells  [][]bool
+		origin Coord
+	}{
+		{"glider at the origin", fromRows(".o.", "..o", "ooo"), Coord{0, 0}},
+		{"negative quadrants", fromRows("oo", "o."), Coord{-1, -1}},
+		{"repeated leaves", fromRows(strings.Repeat("o.", 20), strings.Repeat(".o", 20)), Coord{-13, 5}},
+		{"distant cells", fromRows("o" + strings.Repeat(".", 298) + "o"), Coord{-150, -40}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pattern := Pattern{
+				Metadata: PatternMetadata{Name: "Test", Author: "Nobody", Comments: []string{"A comment."}},
+				Cells:    test.cells,
+				Origin:   &test.origin,
+				Rule:     "B36/S23",
+			}
+
+			var buf bytes.Buffer
+			if err := writePattern(&buf, FormatMacrocell, pattern); err != nil {
+				t.Fatal(err)
+			}
+			got, err := parsePattern(&buf, FormatMacrocell)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(patternRows(got), patternRows(pattern)) {
+				t.Errorf("got %q, want %q", patternRows(got), patternRows(pattern))
+			}
+			if got.Origin == nil || *got.Origin != test.origin {
+				t.Errorf("got origin %v, want %v", got.Origin, test.origin)
+			}
+			if !reflect.DeepEqual(got.Metadata, pattern.Metadata) {
+				t.Errorf("got metadata %+v, want %+v", got.Metadata, pattern.Metadata)
+			}
+			if got.Rule != pattern.Rule {
+				t.Errorf("got rule %q, want %q", got.Rule, pattern.Rule)
+			}
+		})
+	}
+}
+
+func TestMacrocellLargeSparse(t *testing.T) {
+	far := 1 << 29
+	pattern := Pattern{Origin: &Coord{-far, -far}}
+	pattern.Sparse = []Coord{{0, 0}, {1, 0}, {2 * far, 2 * far}, {2*far - 1, 2 * far}}
+
+	var buf bytes.Buffer
+	if err := writeMacrocell(&buf, pattern); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() > 4096 {
+		t.Errorf("got %d bytes for 4 cells", buf.Len())
+	}
+
+	got, err := parseMacrocell(&buf, defaultPatternLimit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Cells != nil || len(got.Sparse) != 4 {
+		t.Fatalf("got %d sparse cells and a %d column matrix, want 4 sparse cells", len(got.Sparse), len(got.Cells))
+	}
+	if width, height := got.size(); width != 2*far+1 || height != 2*far+1 {
+		t.Errorf("got size %dx%d, want %dx%d", width, height, 2*far+1, 2*far+1)
+	}
+
+	game := Game{Universe: CreateUniverseInfinite(testParameters())}
+	game.embedPatternAt(got, got.Origin.X, got.Origin.Y)
+	if game.Universe.AliveCount() != 4 {
+		t.Errorf("got %d alive cells, want 4", game.Universe.AliveCount())
+	}
+	for _, c := range []Coord{{-far, -far}, {1 - far, -far}, {far, far}, {far - 1, far}} {
+		if game.Universe.IsAlive(c.X, c.Y) == 0 {
+			t.Errorf("cell %v is not alive", c)
+		}
+	}
+}
+
+func TestParseMacrocellLimits(t *testing.T) {
+	// Every node refers to the previous one four times, the pattern doubles in both directions on each level.
+	var bomb strings.Builder
+	bomb.WriteString("[M2]\n********$\n")
+	for level := 4; level <= 20; level++ {
+		n := level - 3
+		fmt.Fprintf(&bomb, "%d %d %d %d %d\n", level, n, n, n, n)
+	}
+	if _, err := parseMacrocell(strings.NewReader(bomb.String()), defaultPatternLimit); err == nil || !strings.Contains(err.Error(), "alive cells") {
+		t.Errorf("got error %v for too many cells", err)
+	}
+
+	if _, err := parseMacrocell(strings.NewReader(fmt.Sprintf("[M2]\n*$\n%d 1 0 0 0\n", macrocellMaxLevel+1)), defaultPatternLimit); err == nil {
+		t.Errorf("expected an error for the level above %d", macrocellMaxLevel)
+	}
+}
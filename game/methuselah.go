@@ -0,0 +1,231 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	AnalysisTable = "table"
+	AnalysisJSON  = "json"
+)
+
+// lifespanReport holds the classic methuselah metrics of a pattern.
+type lifespanReport struct {
+	Pattern         string `json:"pattern"`
+	Rule            string `json:"rule"`
+	Stable          bool   `json:"stable"`
+	Lifespan        int    `json:"lifespan"`
+	Period          int    `json:"period"`
+	InitialCells    int    `json:"initialPopulation"`
+	FinalPopulation int    `json:"finalPopulation"`
+	MaxPopulation   int    `json:"maxPopulation"`
+	MaxGeneration   int    `json:"maxGeneration"`
+	Width           int    `json:"width"`
+	Height          int    `json:"height"`
+	Gliders         int    `json:"escapingGliders"`
+	Spaceships      int    `json:"escapingSpaceships"`
+}
+
+// runAnalysis reports the lifespan of the pattern file or of every pattern in the directory.
+func runAnalysis(parameters *UsageParameters) {
+	if *parameters.analyzeFormat != AnalysisTable && *parameters.analyzeFormat != AnalysisJSON {
+		fmt.Printf("Invalid analyze-format specified: %s\n", *parameters.analyzeFormat)
+		os.Exit(3)
+	}
+	limit := SoupMaxGenerations
+	if pflag.Lookup("gens").Changed && *parameters.gens > 0 {
+		limit = *parameters.gens
+	}
+
+	paths, err := patternFiles(*parameters.analyze)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var reports []lifespanReport
+	for _, path := range paths {
+		pattern, err := loadPattern(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		reports = append(reports, analyzeLifespan(path, pattern, parameters, limit))
+	}
+
+	if *parameters.analyzeFormat == AnalysisJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(reports)
+	} else {
+		err = writeLifespanTable(os.Stdout, reports)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// patternFiles returns the source when it is a file, otherwise the pattern files under it.
+func patternFiles(source string) ([]string, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{source}, nil
+	}
+
+	var paths []string
+	err = filepath.WalkDir(source, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && formatExtensions[strings.ToLower(filepath.Ext(path))] != "" {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	return paths, err
+}
+
+// analyzeLifespan runs the pattern on the infinite board until its population becomes periodic.
+// The lifespan is the first generation of the periodic population, the final bounding box
+// and the escaping spaceships are taken at that generation.
+func analyzeLifespan(path string, pattern Pattern, parameters *UsageParameters, limit int) lifespanReport {
+	rule := parameters.rule
+	if *parameters.ruleName == "" && pattern.Rule != "" {
+		if patternRule, err := ParseRule(pattern.Rule); err == nil {
+			rule = patternRule
+		}
+	}
+	isolated := *parameters
+	isolated.rule = rule
+	start := func() Universe {
+		u := isolatedUniverse(isolated)
+		game := Game{Universe: u}
+		game.embedPatternAt(pattern, 0, 0)
+		return u
+	}
+
+	report := lifespanReport{Pattern: path, Rule: rule.String()}
+	u := start()
+	report.InitialCells = u.AliveCount()
+
+	var populations []int
+	for {
+		population := u.AliveCount()
+		populations = append(populations, population)
+		if population > report.MaxPopulation {
+			report.MaxPopulation, report.MaxGeneration = population, u.Generation()
+		}
+		if population == 0 {
+			report.Stable = true
+			report.Lifespan = u.Generation()
+			break
+		}
+		if len(populations) > stabilityMinGenerations {
+			if period := populationPeriod(populations); period > 0 {
+				report.Stable = true
+				report.Period = period
+				report.Lifespan = len(populations) - 1 - period
+				for report.Lifespan > 0 && populations[report.Lifespan-1] == populations[report.Lifespan-1+period] {
+					report.Lifespan--
+				}
+				break
+			}
+		}
+		if u.Generation() >= limit {
+			report.Lifespan = u.Generation()
+			break
+		}
+		u.NextStep()
+	}
+
+	u = start()
+	for u.Generation() < report.Lifespan {
+		u.NextStep()
+	}
+	report.FinalPopulation = u.AliveCount()
+
+	var ships []spaceship
+	core := emptyBounds()
+	for _, object := range separateObjects(u) {
+		bounds := coordBounds(object)
+		if len(object) <= trackingMaxCells {
+			entry := classifyObject(object, u.Parameters(), trackingMaxPeriod)
+			if entry.Class == ClassSpaceship {
+				ships = append(ships, spaceship{object, bounds, entry})
+				continue
+			}
+		}
+		core = core.union(bounds)
+	}
+	for _, ship := range ships {
+		if core.isEmpty() || boundsDistance(ship.bounds.translate(ship.entry.Dx, ship.entry.Dy), core) > boundsDistance(ship.bounds, core) {
+			report.Spaceships++
+			if ship.entry.Code == "xq4_153" {
+				report.Gliders++
+			}
+		} else {
+			core = core.union(ship.bounds)
+		}
+	}
+	if !core.isEmpty() {
+		report.Width = core.BottomRight.X - core.TopLeft.X + 1
+		report.Height = core.BottomRight.Y - core.TopLeft.Y + 1
+	}
+
+	return report
+}
+
+func writeLifespanTable(w io.Writer, reports []lifespanReport) error {
+	nameWidth := len("Pattern")
+	for _, r := range reports {
+		nameWidth = max(nameWidth, len(r.Pattern))
+	}
+
+	_, err := fmt.Fprintf(w, "%-*s  %-8s  %9s  %6s  %5s  %5s  %7s  %7s  %-9s  %7s  %10s\n", nameWidth,
+		"Pattern", "Rule", "Lifespan", "Period", "Start", "Final", "Maximum", "at gen", "Bounding", "Gliders", "Spaceships")
+	if err != nil {
+		return err
+	}
+	for _, r := range reports {
+		lifespan := fmt.Sprint(r.Lifespan)
+		if !r.Stable {
+			lifespan = ">" + lifespan
+		}
+		_, err := fmt.Fprintf(w, "%-*s  %-8s  %9s  %6d  %5d  %5d  %7d  %7d  %-9s  %7d  %10d\n", nameWidth,
+			r.Pattern, r.Rule, lifespan, r.Period, r.InitialCells, r.FinalPopulation, r.MaxPopulation, r.MaxGeneration,
+			fmt.Sprintf("%dx%d", r.Width, r.Height), r.Gliders, r.Spaceships)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
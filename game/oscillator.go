@@ -0,0 +1,252 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// OscillatorMaxGenerations limits the search for a repeated generation.
+const OscillatorMaxGenerations = 1000
+
+// oscillatorReport holds the period and the rotor statistics of an oscillator.
+type oscillatorReport struct {
+	Pattern          string   `json:"pattern"`
+	Error            string   `json:"error,omitempty"`
+	Period           int      `json:"period"`
+	Start            int      `json:"start"`
+	MinPopulation    int      `json:"minPopulation"`
+	MaxPopulation    int      `json:"maxPopulation"`
+	Rotor            int      `json:"rotor"`
+	Stator           int      `json:"stator"`
+	Heat             float64  `json:"heat"`
+	Temperature      float64  `json:"temperature"`
+	Volatility       float64  `json:"volatility"`
+	StrictVolatility float64  `json:"strictVolatility"`
+	HeatMap          []string `json:"heatMap"`
+}
+
+// runOscillatorAnalysis reports the oscillators of the pattern file or of every pattern in the directory.
+func runOscillatorAnalysis(parameters *UsageParameters) {
+	if *parameters.analyzeFormat != AnalysisTable && *parameters.analyzeFormat != AnalysisJSON {
+		fmt.Printf("Invalid analyze-format specified: %s\n", *parameters.analyzeFormat)
+		os.Exit(3)
+	}
+
+	paths, err := patternFiles(*parameters.oscillator)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var reports []oscillatorReport
+	for _, path := range paths {
+		pattern, err := loadPattern(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		reports = append(reports, analyzeOscillator(path, pattern, parameters))
+	}
+
+	if *parameters.analyzeFormat == AnalysisJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(reports)
+	} else {
+		err = writeOscillatorReports(os.Stdout, reports)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// analyzeOscillator runs the pattern until a generation repeats, the generations
+// of one period from the first repeated one are analysed.
+func analyzeOscillator(path string, pattern Pattern, parameters *UsageParameters) oscillatorReport {
+	isolated := *parameters
+	if *parameters.ruleName == "" && pattern.Rule != "" {
+		if rule, err := ParseRule(pattern.Rule); err == nil {
+			isolated.rule = rule
+		}
+	}
+	u := isolatedUniverse(isolated)
+	game := Game{Universe: u}
+	game.embedPatternAt(pattern, 0, 0)
+
+	report := oscillatorReport{Pattern: path}
+	seen := make(map[uint64][]int)
+	var phases []map[Coord]bool
+	for {
+		phase := make(map[Coord]bool, u.AliveCount())
+		u.ForEachAlive(func(cell Coord, age int) {
+			phase[cell] = true
+		})
+
+		// Different generations may have the same hash, so the stored phases are compared.
+		hash := cellsHash(u, Coord{0, 0})
+		if i := slices.IndexFunc(seen[hash], func(generation int) bool {
+			return maps.Equal(phases[generation], phase)
+		}); i >= 0 {
+			start := seen[hash][i]
+			report.Start = start
+			report.Period = u.Generation() - start
+			phases = phases[start:]
+			break
+		}
+		if u.AliveCount() == 0 {
+			report.Error = fmt.Sprintf("dies out at generation %d", u.Generation())
+			return report
+		}
+		if u.Generation() >= OscillatorMaxGenerations {
+			report.Error = fmt.Sprintf("does not repeat in %d generations", OscillatorMaxGenerations)
+			return report
+		}
+
+		seen[hash] = append(seen[hash], u.Generation())
+		phases = append(phases, phase)
+		u.NextStep()
+	}
+
+	report.measure(phases)
+	return report
+}
+
+// measure computes the statistics of the phases of one period.
+func (r *oscillatorReport) measure(phases []map[Coord]bool) {
+	period := len(phases)
+	changes := make(map[Coord]int)
+	aliveIn := make(map[Coord]int)
+	r.MinPopulation = len(phases[0])
+	for k, phase := range phases {
+		r.MinPopulation = min(r.MinPopulation, len(phase))
+		r.MaxPopulation = max(r.MaxPopulation, len(phase))
+
+		next := phases[(k+1)%period]
+		for c := range phase {
+			aliveIn[c]++
+			if !next[c] {
+				changes[c]++
+			}
+		}
+		for c := range next {
+			if !phase[c] {
+				changes[c]++
+			}
+		}
+	}
+
+	total, strict := 0, 0
+	for c, n := range aliveIn {
+		if n == period {
+			r.Stator++
+		} else {
+			r.Rotor++
+			if cellPeriod(phases, c) == period {
+				strict++
+			}
+		}
+		total += changes[c]
+	}
+
+	r.Heat = float64(total) / float64(period)
+	if r.Rotor > 0 {
+		r.Temperature = r.Heat / float64(r.Rotor)
+	}
+	if r.Rotor+r.Stator > 0 {
+		r.Volatility = float64(r.Rotor) / float64(r.Rotor+r.Stator)
+		r.StrictVolatility = float64(strict) / float64(r.Rotor+r.Stator)
+	}
+
+	cells := make([]Coord, 0, len(aliveIn))
+	for c := range aliveIn {
+		cells = append(cells, c)
+	}
+	bounds := coordBounds(cells)
+	for y := bounds.TopLeft.Y; y <= bounds.BottomRight.Y; y++ {
+		var row strings.Builder
+		for x := bounds.TopLeft.X; x <= bounds.BottomRight.X; x++ {
+			c := Coord{x, y}
+			switch {
+			case aliveIn[c] == 0:
+				row.WriteByte('.')
+			case aliveIn[c] == period:
+				row.WriteByte('#')
+			case changes[c] > 9:
+				row.WriteByte('+')
+			default:
+				row.WriteByte(byte('0' + changes[c]))
+			}
+		}
+		r.HeatMap = append(r.HeatMap, row.String())
+	}
+}
+
+// cellPeriod returns the period of the state of the single cell.
+func cellPeriod(phases []map[Coord]bool, c Coord) int {
+	period := len(phases)
+	for d := 1; d < period; d++ {
+		if period%d != 0 {
+			continue
+		}
+		repeats := true
+		for k := range period {
+			if phases[k][c] != phases[(k+d)%period][c] {
+				repeats = false
+				break
+			}
+		}
+		if repeats {
+			return d
+		}
+	}
+	return period
+}
+
+func writeOscillatorReports(w io.Writer, reports []oscillatorReport) error {
+	for i, r := range reports {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		if r.Error != "" {
+			if _, err := fmt.Fprintf(w, "%s: not an oscillator, %s\n", r.Pattern, r.Error); err != nil {
+				return err
+			}
+			continue
+		}
+
+		fmt.Fprintf(w, "%s\n", r.Pattern)
+		fmt.Fprintf(w, "  Period: %d from generation %d; Population: %d-%d\n", r.Period, r.Start, r.MinPopulation, r.MaxPopulation)
+		fmt.Fprintf(w, "  Rotor: %d; Stator: %d; Heat: %.2f; Temperature: %.2f; Volatility: %.2f; Strict volatility: %.2f\n",
+			r.Rotor, r.Stator, r.Heat, r.Temperature, r.Volatility, r.StrictVolatility)
+		fmt.Fprintf(w, "  Heat map: # stator, 1-9 state changes of the rotor cell per period\n")
+		for _, row := range r.HeatMap {
+			if _, err := fmt.Fprintf(w, "  %s\n", row); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import "testing"
+
+func TestAnalyzeOscillator(t *testing.T) {
+	tests := []struct {
+		name   string
+		rows   []string
+		period int
+		rotor  int
+		stator int
+		err    string
+	}{
+		{"block", []string{"oo", "oo"}, 1, 0, 4, ""},
+		{"blinker", []string{"ooo"}, 2, 4, 1, ""},
+		{"toad", []string{".ooo", "ooo."}, 2, 8, 2, ""},
+		{"beacon", []string{"oo..", "oo..", "..oo", "..oo"}, 2, 2, 6, ""},
+		{"domino", []string{"oo"}, 0, 0, 0, "dies out at generation 1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parameters := testParameters()
+			ruleName := ""
+			parameters.ruleName = &ruleName
+
+			report := analyzeOscillator(test.name, Pattern{Cells: fromRows(test.rows...)}, parameters)
+			if report.Error != test.err {
+				t.Fatalf("got error %q, want %q", report.Error, test.err)
+			}
+			if report.Period != test.period || report.Rotor != test.rotor || report.Stator != test.stator {
+				t.Errorf("got period %d, rotor %d, stator %d, want %d, %d, %d",
+					report.Period, report.Rotor, report.Stator, test.period, test.rotor, test.stator)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// maxPatternDimension limits the width and height of the patterns read into a matrix,
+// the file headers and run counts are not trusted.
+const maxPatternDimension = 8192
+
+// patternLimit is the largest width and height of the patterns read for a universe.
+type patternLimit struct {
+	width  int
+	height int
+}
+
+// defaultPatternLimit is the limit of the infinite universe, only the matrices are bounded.
+var defaultPatternLimit = patternLimit{math.MaxInt, math.MaxInt}
+
+// boardLimit returns the limit of the patterns put into the universe of the board type and size.
+func boardLimit(boardType string, width int, height int) patternLimit {
+	if boardType != "boarded" {
+		return defaultPatternLimit
+	}
+	return patternLimit{width, height}
+}
+
+// dense returns the limit of the patterns read into a matrix.
+func (l patternLimit) dense() patternLimit {
+	return patternLimit{min(l.width, maxPatternDimension), min(l.height, maxPatternDimension)}
+}
+
+func (l patternLimit) check(width int, height int) error {
+	if width < 0 || height < 0 || width > l.width || height > l.height {
+		return fmt.Errorf("pattern size %dx%d exceeds %dx%d", width, height, l.width, l.height)
+	}
+	return nil
+}
+
+type PatternMetadata struct {
+	Name     string
+	Author   string
+	Comments []string
+}
+
+// Pattern is a rectangular piece of the universe, Cells are indexed as [x][y].
+// Origin is set when the format stores absolute coordinates of the cells,
+// it is the universe coordinate of Cells[0][0]. Rule is empty when the
+// format or the file doesn't specify it. Large sparse patterns keep only
+// the alive cells relative to Origin in Sparse, Cells is nil for them.
+type Pattern struct {
+	Metadata PatternMetadata
+	Cells    [][]bool
+	Origin   *Coord
+	Rule     string
+	Sparse   []Coord
+}
+
+// Title returns a short description of the pattern to show in the header.
+func (m PatternMetadata) Title() string {
+	if m.Name == "" {
+		return ""
+	}
+	if m.Author == "" {
+		return m.Name
+	}
+	return fmt.Sprintf("%s by %s", m.Name, m.Author)
+}
+
+// merge combines metadata of several patterns composed into one universe.
+func (m PatternMetadata) merge(other PatternMetadata) PatternMetadata {
+	merged := PatternMetadata{
+		Name:     joinDistinct(m.Name, other.Name, " + "),
+		Author:   joinDistinct(m.Author, other.Author, ", "),
+		Comments: append(append([]string(nil), m.Comments...), other.Comments...),
+	}
+	return merged
+}
+
+func joinDistinct(a string, b string, sep string) string {
+	if a == "" || a == b {
+		return b
+	}
+	if b == "" || strings.Contains(a, b) {
+		return a
+	}
+	return a + sep + b
+}
+
+// sparsePatternFromUniverse is patternFromUniverse for the formats writing the cells sparsely.
+func sparsePatternFromUniverse(u Universe, metadata PatternMetadata) Pattern {
+	pattern := Pattern{Metadata: metadata}
+	pattern.setSparse(aliveCoords(u))
+	return pattern
+}
+
+// patternFromUniverse cuts the smallest rectangle containing all alive cells.
+func patternFromUniverse(u Universe, metadata PatternMetadata) Pattern {
+	topLeft := Coord{math.MaxInt, math.MaxInt}
+	bottomRight := Coord{math.MinInt, math.MinInt}
+	u.ForEachAlive(func(cell Coord, age int) {
+		topLeft.X = min(topLeft.X, cell.X)
+		topLeft.Y = min(topLeft.Y, cell.Y)
+		bottomRight.X = max(bottomRight.X, cell.X)
+		bottomRight.Y = max(bottomRight.Y, cell.Y)
+	})
+
+	pattern := Pattern{Metadata: metadata}
+	if topLeft.X > bottomRight.X {
+		return pattern
+	}
+	pattern.Origin = &topLeft
+
+	pattern.Cells = newMatrix(bottomRight.X-topLeft.X+1, bottomRight.Y-topLeft.Y+1)
+	u.ForEachAlive(func(cell Coord, age int) {
+		pattern.Cells[cell.X-topLeft.X][cell.Y-topLeft.Y] = true
+	})
+
+	return pattern
+}
+
+func (p *Pattern) origin() Coord {
+	if p.Origin == nil {
+		return Coord{0, 0}
+	}
+	return *p.Origin
+}
+
+// setCells builds the matrix from absolute cell coordinates.
+func (p *Pattern) setCells(cells []Coord) {
+	if len(cells) == 0 {
+		p.Cells = nil
+		return
+	}
+
+	topLeft, bottomRight := cells[0], cells[0]
+	for _, c := range cells {
+		topLeft.X = min(topLeft.X, c.X)
+		topLeft.Y = min(topLeft.Y, c.Y)
+		bottomRight.X = max(bottomRight.X, c.X)
+		bottomRight.Y = max(bottomRight.Y, c.Y)
+	}
+
+	p.Origin = &topLeft
+	p.Cells = newMatrix(bottomRight.X-topLeft.X+1, bottomRight.Y-topLeft.Y+1)
+	for _, c := range cells {
+		p.Cells[c.X-topLeft.X][c.Y-topLeft.Y] = true
+	}
+}
+
+// setSparse keeps the alive cells given in absolute coordinates without building the matrix.
+func (p *Pattern) setSparse(cells []Coord) {
+	p.Cells = nil
+	if len(cells) == 0 {
+		p.Sparse = nil
+		return
+	}
+
+	topLeft := cells[0]
+	for _, c := range cells {
+		topLeft.X = min(topLeft.X, c.X)
+		topLeft.Y = min(topLeft.Y, c.Y)
+	}
+
+	p.Origin = &topLeft
+	p.Sparse = make([]Coord, len(cells))
+	for i, c := range cells {
+		p.Sparse[i] = Coord{c.X - topLeft.X, c.Y - topLeft.Y}
+	}
+}
+
+// size returns the width and the height of the pattern.
+func (p *Pattern) size() (int, int) {
+	if p.Sparse == nil {
+		return matrixSize(p.Cells)
+	}
+	width, height := 0, 0
+	for _, c := range p.Sparse {
+		width = max(width, c.X+1)
+		height = max(height, c.Y+1)
+	}
+	return width, height
+}
+
+// forEachAlive calls fn with the alive cells relative to the top left corner of the pattern.
+func (p *Pattern) forEachAlive(fn func(x int, y int)) {
+	if p.Sparse != nil {
+		for _, c := range p.Sparse {
+			fn(c.X, c.Y)
+		}
+		return
+	}
+	for x, column := range p.Cells {
+		for y, alive := range column {
+			if alive {
+				fn(x, y)
+			}
+		}
+	}
+}
+
+// rule returns the pattern rule notation falling back to Conway's rule.
+func (p *Pattern) rule() string {
+	if p.Rule == "" {
+		return ConwayRule.String()
+	}
+	return p.Rule
+}
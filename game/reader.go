@@ -21,35 +21,102 @@ package game
 
 import (
 	"bufio"
+	"io"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
 )
 
-func readFile(source *string) [][]bool {
+const (
+	FormatCells     = "cells"
+	FormatRLE       = "rle"
+	FormatLife105   = "life105"
+	FormatLife106   = "life106"
+	FormatMacrocell = "mc"
+)
+
+var formatExtensions = map[string]string{
+	".cells": FormatCells,
+	".rle":   FormatRLE,
+	".lif":   FormatLife106,
+	".life":  FormatLife106,
+	".mc":    FormatMacrocell,
+}
+
+// formatOf returns the pattern format matching the file extension.
+func formatOf(path string) string {
+	if format, ok := formatExtensions[strings.ToLower(filepath.Ext(path))]; ok {
+		return format
+	}
+	return FormatCells
+}
+
+// readFile loads the pattern and terminates the program if it can't be read.
+func readFile(source *string) Pattern {
 
-	file, err := os.Open(*source)
+	pattern, err := loadPattern(*source)
 	if err != nil {
 		log.Fatal(err)
 	}
-	defer func(file *os.File) {
-		err := file.Close()
-		if err != nil {
-			log.Fatal(err)
-		}
-	}(file)
 
+	return pattern
+}
+
+// loadPattern reads a pattern file choosing the format by the file extension,
+// plaintext .cells format is used for unknown extensions. Both Life 1.05 and
+// Life 1.06 use .lif or .life extension, the version is detected by the header.
+func loadPattern(source string) (Pattern, error) {
+
+	file, err := os.Open(source)
+	if err != nil {
+		return Pattern{}, err
+	}
+	defer file.Close()
+
+	return parsePattern(file, formatOf(source))
+}
+
+func parsePattern(r io.Reader, format string) (Pattern, error) {
+	return parsePatternWithin(r, format, defaultPatternLimit)
+}
+
+// parsePatternWithin reads the pattern failing as soon as it exceeds the limit,
+// before the cells are allocated.
+func parsePatternWithin(r io.Reader, format string, limit patternLimit) (Pattern, error) {
+
+	switch format {
+	case FormatRLE:
+		return parseRLE(r, limit)
+	case FormatLife105, FormatLife106:
+		return parseLife(r, limit)
+	case FormatMacrocell:
+		return parseMacrocell(r, limit)
+	default:
+		return parseCells(r, limit)
+	}
+}
+
+func parseCells(r io.Reader, limit patternLimit) (Pattern, error) {
+
+	var pattern Pattern
 	var matrix [][]bool
 	maxCols := 0
-	scanner := bufio.NewScanner(file)
+	limit = limit.dense()
+	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line := scanner.Text()
 		if len(line) > 0 && line[0] == '!' {
+			pattern.Metadata.parseCellsComment(line[1:])
 			continue
 		}
 		cols := len(line)
 		if cols > maxCols {
 			maxCols = cols
 		}
+		if err := limit.check(maxCols, len(matrix)+1); err != nil {
+			return pattern, err
+		}
 		row := make([]bool, cols)
 		for i, ch := range line {
 			if ch == 'O' {
@@ -62,7 +129,7 @@ func readFile(source *string) [][]bool {
 	}
 
 	if err := scanner.Err(); err != nil {
-		log.Fatal(err)
+		return pattern, err
 	}
 
 	rows := len(matrix)
@@ -77,5 +144,19 @@ func readFile(source *string) [][]bool {
 		}
 	}
 
-	return transposed
+	pattern.Cells = transposed
+	return pattern, nil
+}
+
+// parseCellsComment handles "!Name:" and "!Author:" lines,
+// the rest of non-empty comment lines are kept as they are.
+func (m *PatternMetadata) parseCellsComment(comment string) {
+	comment = strings.TrimSpace(comment)
+	if value, ok := strings.CutPrefix(comment, "Name:"); ok {
+		m.Name = strings.TrimSpace(value)
+	} else if value, ok := strings.CutPrefix(comment, "Author:"); ok {
+		m.Author = strings.TrimSpace(value)
+	} else if comment != "" {
+		m.Comments = append(m.Comments, comment)
+	}
 }
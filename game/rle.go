@@ -0,0 +1,230 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const rleLineLength = 70
+
+// parseRLE reads the run length encoded format, "#N", "#O" and "#C" lines
+// are stored as the pattern name, author and comments. The matrix is sized
+// by the decoded cells, the header only has to be large enough for them.
+func parseRLE(r io.Reader, limit patternLimit) (Pattern, error) {
+
+	var pattern Pattern
+	var cells []Coord
+	limit = limit.dense()
+	headerWidth, headerHeight := -1, -1
+	width, height := 0, 0
+	x, y, count := 0, 0, 0
+	finished := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() && !finished {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if line[0] == '#' {
+			pattern.Metadata.parseRLEComment(line)
+			continue
+		}
+		if line[0] == 'x' && strings.Contains(line, "=") {
+			w, h, rule, err := parseRLEHeader(line)
+			if err != nil {
+				return pattern, err
+			}
+			if err := limit.check(w, h); err != nil {
+				return pattern, fmt.Errorf("invalid RLE header %q: %w", line, err)
+			}
+			headerWidth, headerHeight = w, h
+			pattern.Rule = rule
+			continue
+		}
+
+		for _, ch := range line {
+			switch {
+			case ch >= '0' && ch <= '9':
+				count = count*10 + int(ch-'0')
+				if count > max(limit.width, limit.height) {
+					return pattern, fmt.Errorf("RLE run count %d exceeds %d", count, max(limit.width, limit.height))
+				}
+				continue
+			case ch == 'b' || ch == '.':
+				x += max(count, 1)
+			case ch == '$':
+				y += max(count, 1)
+				x = 0
+			case ch == '!':
+				finished = true
+			case ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z':
+				if err := limit.check(x+max(count, 1), y+1); err != nil {
+					return pattern, err
+				}
+				for range max(count, 1) {
+					cells = append(cells, Coord{x, y})
+					x++
+				}
+				width, height = max(width, x), max(height, y+1)
+			case ch == ' ' || ch == '\t':
+			default:
+				return pattern, fmt.Errorf("unexpected symbol %q in RLE data", ch)
+			}
+			count = 0
+			if finished {
+				break
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return pattern, err
+	}
+	if headerWidth >= 0 && (width > headerWidth || height > headerHeight) {
+		return pattern, fmt.Errorf("RLE data of %dx%d cells does not fit the header size %dx%d", width, height, headerWidth, headerHeight)
+	}
+
+	pattern.Cells = newMatrix(width, height)
+	for _, c := range cells {
+		pattern.Cells[c.X][c.Y] = true
+	}
+
+	return pattern, nil
+}
+
+func parseRLEHeader(line string) (int, int, string, error) {
+	width, height, rule := 0, 0, ""
+	for _, field := range strings.Split(line, ",") {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return 0, 0, "", fmt.Errorf("invalid RLE header %q", line)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		var err error
+		if key == "x" {
+			width, err = strconv.Atoi(value)
+		} else if key == "y" {
+			height, err = strconv.Atoi(value)
+		} else if key == "rule" {
+			rule = value
+		}
+		if err != nil {
+			return 0, 0, "", fmt.Errorf("invalid RLE header %q", line)
+		}
+	}
+	return width, height, rule, nil
+}
+
+func (m *PatternMetadata) parseRLEComment(line string) {
+	if len(line) < 2 {
+		return
+	}
+	value := strings.TrimSpace(line[2:])
+	switch line[1] {
+	case 'N':
+		m.Name = value
+	case 'O':
+		m.Author = value
+	case 'C', 'c':
+		if value != "" {
+			m.Comments = append(m.Comments, value)
+		}
+	}
+}
+
+func writeRLE(w io.Writer, pattern Pattern) error {
+
+	bw := bufio.NewWriter(w)
+	width, height := matrixSize(pattern.Cells)
+
+	if pattern.Metadata.Name != "" {
+		fmt.Fprintf(bw, "#N %s\n", pattern.Metadata.Name)
+	}
+	if pattern.Metadata.Author != "" {
+		fmt.Fprintf(bw, "#O %s\n", pattern.Metadata.Author)
+	}
+	for _, comment := range pattern.Metadata.Comments {
+		fmt.Fprintf(bw, "#C %s\n", comment)
+	}
+	fmt.Fprintf(bw, "x = %d, y = %d, rule = %s\n", width, height, pattern.rule())
+
+	line := ""
+	emit := func(count int, tag byte) {
+		token := string(tag)
+		if count > 1 {
+			token = strconv.Itoa(count) + token
+		}
+		if len(line)+len(token) > rleLineLength {
+			fmt.Fprintln(bw, line)
+			line = ""
+		}
+		line += token
+	}
+
+	row := 0
+	for y := range height {
+		runs := rleRow(pattern.Cells, y, width)
+		if len(runs) == 0 {
+			continue
+		}
+		if y > row {
+			emit(y-row, '$')
+			row = y
+		}
+		for _, run := range runs {
+			emit(run.count, run.tag)
+		}
+	}
+	emit(1, '!')
+	fmt.Fprintln(bw, line)
+
+	return bw.Flush()
+}
+
+type rleRun struct {
+	count int
+	tag   byte
+}
+
+// rleRow encodes a single row, trailing dead cells are omitted.
+func rleRow(cells [][]bool, y int, width int) []rleRun {
+	var runs []rleRun
+	for x := 0; x < width; {
+		alive := cells[x][y]
+		start := x
+		for x < width && cells[x][y] == alive {
+			x++
+		}
+		if alive {
+			runs = append(runs, rleRun{x - start, 'o'})
+		} else if x < width {
+			runs = append(runs, rleRun{x - start, 'b'})
+		}
+	}
+	return runs
+}
@@ -0,0 +1,232 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// rows shows the matrix row by row, 'o' is an alive cell and '.' a dead one.
+func rows(cells [][]bool) []string {
+	width, height := matrixSize(cells)
+	result := make([]string, height)
+	for y := range height {
+		var b strings.Builder
+		for x := range width {
+			if cells[x][y] {
+				b.WriteByte('o')
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		result[y] = b.String()
+	}
+	return result
+}
+
+func fromRows(lines ...string) [][]bool {
+	cells := newMatrix(len(lines[0]), len(lines))
+	for y, line := range lines {
+		for x, ch := range line {
+			cells[x][y] = ch == 'o'
+		}
+	}
+	return cells
+}
+
+func TestParseRLE(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []string
+	}{
+		{"glider", "x = 3, y = 3\nbo$2bo$3o!", []string{".o.", "..o", "ooo"}},
+		{"run counts", "x = 13, y = 1\n10o2bo!", []string{"oooooooooo..o"}},
+		{"sized by the cells", "x = 100, y = 100\no!", []string{"o"}},
+		{"multi-digit run", "12o!", []string{"oooooooooooo"}},
+		{"row runs", "x = 2, y = 4\no3$bo!", []string{"o.", "..", "..", ".o"}},
+		{"split lines", "x = 3, y = 2\nob\no$\n3o!", []string{"o.o", "ooo"}},
+		{"data after end", "x = 1, y = 1\no!\n3o$3o!", []string{"o"}},
+		{"dots and other states", "x = 3, y = 1\nA.x!", []string{"o.o"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pattern, err := parseRLE(strings.NewReader(test.data), defaultPatternLimit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := rows(pattern.Cells); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseRLEMetadata(t *testing.T) {
+	data := "#N Glider\n#O Richard K. Guy\n#C The smallest spaceship.\n#C Found in 1969.\n" +
+		"x = 3, y = 3, rule = B36/S23\nbo$2bo$3o!\n"
+
+	pattern, err := parseRLE(strings.NewReader(data), defaultPatternLimit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := PatternMetadata{
+		Name:     "Glider",
+		Author:   "Richard K. Guy",
+		Comments: []string{"The smallest spaceship.", "Found in 1969."},
+	}
+	if !reflect.DeepEqual(pattern.Metadata, want) {
+		t.Errorf("got metadata %+v, want %+v", pattern.Metadata, want)
+	}
+	if pattern.Rule != "B36/S23" {
+		t.Errorf("got rule %q, want B36/S23", pattern.Rule)
+	}
+}
+
+func TestParseRLEErrors(t *testing.T) {
+	for _, data := range []string{
+		"x = a, y = 1\no!",
+		"x = 1 y = 1\no!",
+		"o?o!",
+		"x = 100000, y = 100000\no!",
+		"x = -1, y = 1\no!",
+		"x = 2, y = 1\n3o!",
+		"x = 3, y = 1\no$o!",
+		"100000o!",
+		"99999999999999999999o!",
+		"8000b8000bo!",
+		"10000$o!",
+	} {
+		if _, err := parseRLE(strings.NewReader(data), defaultPatternLimit); err == nil {
+			t.Errorf("%q: expected an error", data)
+		}
+	}
+}
+
+func TestParsePatternWithinBoard(t *testing.T) {
+	limit := boardLimit("boarded", 10, 10)
+	for _, test := range []struct {
+		format string
+		data   string
+	}{
+		{FormatRLE, "x = 100, y = 100\no!"},
+		{FormatRLE, "20bo!"},
+		{FormatCells, "..........O"},
+		{FormatLife106, "#Life 1.06\n0 0\n0 10"},
+		{FormatMacrocell, "[M2] (go-life)\n.......*$\n*$\n4 2 1 0 0\n"},
+	} {
+		if _, err := parsePatternWithin(strings.NewReader(test.data), test.format, limit); err == nil {
+			t.Errorf("%s %q: expected an error", test.format, test.data)
+		}
+	}
+
+	pattern, err := parsePatternWithin(strings.NewReader("x = 10, y = 10\n9bo$8$o!"), FormatRLE, limit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if width, height := pattern.size(); width != 10 || height != 10 {
+		t.Errorf("got size %dx%d, want 10x10", width, height)
+	}
+}
+
+func TestWriteRLE(t *testing.T) {
+	tests := []struct {
+		name string
+		rows []string
+		want string
+	}{
+		{"glider", []string{".o.", "..o", "ooo"}, "bo$2bo$3o!"},
+		{"trailing dead cells", []string{"oo..", "o..."}, "2o$o!"},
+		{"empty rows", []string{"o", ".", ".", "o"}, "o3$o!"},
+		{"leading empty rows", []string{"..", ".o"}, "$bo!"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeRLE(&buf, Pattern{Cells: fromRows(test.rows...)}); err != nil {
+				t.Fatal(err)
+			}
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			if got := lines[len(lines)-1]; got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestWriteRLEWrapsLines(t *testing.T) {
+	row := strings.Repeat("o.", 100)
+	pattern := Pattern{Cells: fromRows(row, row)}
+
+	var buf bytes.Buffer
+	if err := writeRLE(&buf, pattern); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) < 3 {
+		t.Fatalf("expected the data to be wrapped, got %q", buf.String())
+	}
+	for _, line := range lines {
+		if len(line) > rleLineLength {
+			t.Errorf("line longer than %d characters: %q", rleLineLength, line)
+		}
+	}
+	if !strings.HasSuffix(lines[len(lines)-1], "!") {
+		t.Errorf("the last line %q does not end the pattern", lines[len(lines)-1])
+	}
+}
+
+func TestRLERoundTrip(t *testing.T) {
+	wide := strings.Repeat("oo.", 40)
+	pattern := Pattern{
+		Metadata: PatternMetadata{Name: "Test", Author: "Nobody", Comments: []string{"first", "second"}},
+		Cells: fromRows(
+			wide,
+			strings.Repeat(".", len(wide)),
+			strings.Repeat(".", len(wide)),
+			strings.Repeat("o", len(wide)),
+			"o"+strings.Repeat(".", len(wide)-2)+"o",
+		),
+		Rule: "B3/S23",
+	}
+
+	var buf bytes.Buffer
+	if err := writePattern(&buf, FormatRLE, pattern); err != nil {
+		t.Fatal(err)
+	}
+	got, err := parsePattern(&buf, FormatRLE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rows(got.Cells), rows(pattern.Cells)) {
+		t.Errorf("cells differ after the round trip:\n%q\n%q", rows(got.Cells), rows(pattern.Cells))
+	}
+	if !reflect.DeepEqual(got.Metadata, pattern.Metadata) {
+		t.Errorf("got metadata %+v, want %+v", got.Metadata, pattern.Metadata)
+	}
+	if got.Rule != pattern.Rule {
+		t.Errorf("got rule %q, want %q", got.Rule, pattern.Rule)
+	}
+}
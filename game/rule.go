@@ -0,0 +1,113 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rule is an outer totalistic Life-like rule, birth and survival are indexed
+// by the number of alive neighbours.
+type Rule struct {
+	birth    [9]bool
+	survival [9]bool
+}
+
+var ConwayRule = Rule{
+	birth:    [9]bool{3: true},
+	survival: [9]bool{2: true, 3: true},
+}
+
+// ParseRule accepts the B/S notation (B36/S23 or B36S23) and the S/B notation (23/36).
+func ParseRule(notation string) (Rule, error) {
+	var rule Rule
+
+	normalized := strings.ToUpper(strings.TrimSpace(notation))
+	first, second, ok := strings.Cut(normalized, "/")
+	if !ok && strings.HasPrefix(normalized, "B") {
+		first, second, ok = strings.Cut(normalized, "S")
+		second = "S" + second
+	}
+	if !ok {
+		return rule, fmt.Errorf("invalid rule %q, expected B3/S23 or 23/3 notation", notation)
+	}
+
+	birth, survival := second, first
+	if strings.HasPrefix(first, "B") || strings.HasPrefix(second, "S") {
+		birth, survival = first, second
+	}
+	birth = strings.TrimPrefix(birth, "B")
+	survival = strings.TrimPrefix(survival, "S")
+
+	if err := parseRuleCounts(birth, &rule.birth); err != nil {
+		return rule, fmt.Errorf("invalid rule %q: %w", notation, err)
+	}
+	if err := parseRuleCounts(survival, &rule.survival); err != nil {
+		return rule, fmt.Errorf("invalid rule %q: %w", notation, err)
+	}
+	if rule.birth[0] {
+		return rule, fmt.Errorf("invalid rule %q: B0 rules are not supported", notation)
+	}
+
+	return rule, nil
+}
+
+func parseRuleCounts(counts string, target *[9]bool) error {
+	for _, ch := range counts {
+		if ch < '0' || ch > '8' {
+			return fmt.Errorf("unexpected neighbour count %q", ch)
+		}
+		target[ch-'0'] = true
+	}
+	return nil
+}
+
+// Next returns whether the cell is alive on the next step.
+func (r Rule) Next(neighbours int, alive bool) bool {
+	if alive {
+		return r.survival[neighbours]
+	}
+	return r.birth[neighbours]
+}
+
+func (r Rule) String() string {
+	var sb strings.Builder
+	sb.WriteString("B")
+	for i, on := range r.birth {
+		if on {
+			sb.WriteByte(byte('0' + i))
+		}
+	}
+	sb.WriteString("/S")
+	for i, on := range r.survival {
+		if on {
+			sb.WriteByte(byte('0' + i))
+		}
+	}
+	return sb.String()
+}
+
+// survivalBirthString returns the rule in the S/B notation used by Life 1.05.
+func (r Rule) survivalBirthString() string {
+	notation := r.String()
+	birth, survival, _ := strings.Cut(notation, "/")
+	return strings.TrimPrefix(survival, "S") + "/" + strings.TrimPrefix(birth, "B")
+}
@@ -0,0 +1,111 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import "testing"
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		notation string
+		want     string
+	}{
+		{"B3/S23", "B3/S23"},
+		{"b3/s23", "B3/S23"},
+		{"B3S23", "B3/S23"},
+		{"23/3", "B3/S23"},
+		{"S23/B3", "B3/S23"},
+		{"B36/S23", "B36/S23"},
+		{"23/36", "B36/S23"},
+		{" B2/S ", "B2/S"},
+		{"B1357/S02468", "B1357/S02468"},
+	}
+
+	for _, test := range tests {
+		rule, err := ParseRule(test.notation)
+		if err != nil {
+			t.Errorf("%q: %v", test.notation, err)
+			continue
+		}
+		if got := rule.String(); got != test.want {
+			t.Errorf("%q: got %q, want %q", test.notation, got, test.want)
+		}
+	}
+}
+
+func TestParseRuleErrors(t *testing.T) {
+	for _, notation := range []string{"", "Life", "B3", "B9/S23", "B3/S2x", "B03/S23"} {
+		if _, err := ParseRule(notation); err == nil {
+			t.Errorf("%q: expected an error", notation)
+		}
+	}
+}
+
+func TestRuleRoundTrip(t *testing.T) {
+	for _, notation := range []string{"B3/S23", "B36/S23", "B2/S", "B3678/S34678", "B1/S012345678"} {
+		rule, err := ParseRule(notation)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, written := range []string{rule.String(), rule.survivalBirthString()} {
+			parsed, err := ParseRule(written)
+			if err != nil {
+				t.Errorf("%q: %v", written, err)
+			} else if parsed != rule {
+				t.Errorf("%q: got %v, want %v", written, parsed, rule)
+			}
+		}
+	}
+}
+
+func TestRuleNext(t *testing.T) {
+	for n := range 9 {
+		if got, want := ConwayRule.Next(n, false), n == 3; got != want {
+			t.Errorf("dead cell with %d neighbours: got %v, want %v", n, got, want)
+		}
+		if got, want := ConwayRule.Next(n, true), n == 2 || n == 3; got != want {
+			t.Errorf("alive cell with %d neighbours: got %v, want %v", n, got, want)
+		}
+	}
+}
+
+func TestApplyPatternRule(t *testing.T) {
+	highLife, _ := ParseRule("B36/S23")
+	tests := []struct {
+		name     string
+		ruleName string
+		pattern  string
+		want     Rule
+	}{
+		{"pattern rule", "", "B36/S23", highLife},
+		{"no pattern rule", "", "", ConwayRule},
+		{"explicit rule wins", "B3/S23", "B36/S23", ConwayRule},
+		{"unsupported pattern rule", "", "Life", ConwayRule},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := UsageParameters{ruleName: &test.ruleName, rule: ConwayRule}
+			p.applyPatternRule(Pattern{Rule: test.pattern})
+			if p.rule != test.want {
+				t.Errorf("got %v, want %v", p.rule, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// placement describes a single pattern file to put into the universe.
+// Spec format is path[@x,y][:transform[:transform...]], e.g. glider.cells@10,20:rot90.
+// Without an offset the pattern is centered on the screen.
+type placement struct {
+	path       string
+	offset     *Coord
+	transforms []string
+}
+
+var matrixTransforms = map[string]func([][]bool) [][]bool{
+	"rot90":  rotate90,
+	"rot180": func(m [][]bool) [][]bool { return rotate90(rotate90(m)) },
+	"rot270": func(m [][]bool) [][]bool { return rotate90(rotate90(rotate90(m))) },
+	"flipx":  flipX,
+	"flipy":  flipY,
+}
+
+// parsePlacement reads the transforms and the offset from the end of the spec,
+// so the path itself may contain ':' and '@'.
+func parsePlacement(spec string) (placement, error) {
+	p := placement{path: spec}
+
+	for {
+		colon := strings.LastIndex(p.path, ":")
+		if colon < 0 || !isTransformName(p.path[colon+1:]) {
+			break
+		}
+		t := p.path[colon+1:]
+		if _, ok := matrixTransforms[t]; !ok {
+			return p, fmt.Errorf("unknown transform %q in %q, allowed values are rot90, rot180, rot270, flipx, flipy", t, spec)
+		}
+		p.transforms = append([]string{t}, p.transforms...)
+		p.path = p.path[:colon]
+	}
+
+	if at := strings.LastIndex(p.path, "@"); at >= 0 && strings.Contains(p.path[at+1:], ",") {
+		xy := strings.Split(p.path[at+1:], ",")
+		if len(xy) != 2 {
+			return p, fmt.Errorf("invalid offset in %q, expected @x,y", spec)
+		}
+		x, errX := strconv.Atoi(strings.TrimSpace(xy[0]))
+		y, errY := strconv.Atoi(strings.TrimSpace(xy[1]))
+		if errX != nil || errY != nil {
+			return p, fmt.Errorf("invalid offset in %q, expected @x,y", spec)
+		}
+		p.offset = &Coord{x, y}
+		p.path = p.path[:at]
+	}
+
+	if p.path == "" {
+		return p, fmt.Errorf("missing file name in %q", spec)
+	}
+
+	return p, nil
+}
+
+// isTransformName tells a transform suffix from a part of the path such as C:\ on Windows.
+func isTransformName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, ch := range s {
+		if !(ch >= 'a' && ch <= 'z' || ch >= '0' && ch <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// readScene reads a scene description file: one placement spec per line,
+// empty lines and lines starting with '#' are ignored. Relative pattern
+// paths are resolved against the directory of the scene file.
+func readScene(source string) ([]placement, error) {
+	file, err := os.Open(source)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var placements []placement
+	scanner := bufio.NewScanner(file)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		p, err := parsePlacement(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", source, lineNo, err)
+		}
+		if !filepath.IsAbs(p.path) {
+			p.path = filepath.Join(filepath.Dir(source), p.path)
+		}
+		placements = append(placements, p)
+	}
+
+	return placements, scanner.Err()
+}
+
+// coordTransforms are the matrixTransforms for the cells of a sparse pattern of the width and height.
+var coordTransforms = map[string]func(c Coord, width int, height int) Coord{
+	"rot90":  func(c Coord, width int, height int) Coord { return Coord{height - 1 - c.Y, c.X} },
+	"rot180": func(c Coord, width int, height int) Coord { return Coord{width - 1 - c.X, height - 1 - c.Y} },
+	"rot270": func(c Coord, width int, height int) Coord { return Coord{c.Y, width - 1 - c.X} },
+	"flipx":  func(c Coord, width int, height int) Coord { return Coord{width - 1 - c.X, c.Y} },
+	"flipy":  func(c Coord, width int, height int) Coord { return Coord{c.X, height - 1 - c.Y} },
+}
+
+func (p placement) apply(pattern Pattern) Pattern {
+	for _, t := range p.transforms {
+		if pattern.Sparse == nil {
+			pattern.Cells = matrixTransforms[t](pattern.Cells)
+			continue
+		}
+
+		width, height := pattern.size()
+		cells := make([]Coord, len(pattern.Sparse))
+		for i, c := range pattern.Sparse {
+			cells[i] = coordTransforms[t](c, width, height)
+		}
+		pattern.Sparse = cells
+	}
+	return pattern
+}
+
+func matrixSize(m [][]bool) (int, int) {
+	if len(m) == 0 {
+		return 0, 0
+	}
+	return len(m), len(m[0])
+}
+
+func newMatrix(width int, height int) [][]bool {
+	m := make([][]bool, width)
+	for i := range m {
+		m[i] = make([]bool, height)
+	}
+	return m
+}
+
+// rotate90 rotates the matrix clockwise as it is seen on the screen.
+func rotate90(m [][]bool) [][]bool {
+	width, height := matrixSize(m)
+	rotated := newMatrix(height, width)
+	for x := range width {
+		for y := range height {
+			rotated[height-1-y][x] = m[x][y]
+		}
+	}
+	return rotated
+}
+
+func flipX(m [][]bool) [][]bool {
+	width, height := matrixSize(m)
+	flipped := newMatrix(width, height)
+	for x := range width {
+		for y := range height {
+			flipped[width-1-x][y] = m[x][y]
+		}
+	}
+	return flipped
+}
+
+func flipY(m [][]bool) [][]bool {
+	width, height := matrixSize(m)
+	flipped := newMatrix(width, height)
+	for x := range width {
+		for y := range height {
+			flipped[x][height-1-y] = m[x][y]
+		}
+	}
+	return flipped
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePlacement(t *testing.T) {
+	tests := []struct {
+		spec       string
+		path       string
+		offset     *Coord
+		transforms []string
+	}{
+		{"glider.cells", "glider.cells", nil, nil},
+		{"glider.cells@10,-20", "glider.cells", &Coord{10, -20}, nil},
+		{"glider.cells@10,20:rot90:flipx", "glider.cells", &Coord{10, 20}, []string{"rot90", "flipx"}},
+		{"glider.cells:rot180", "glider.cells", nil, []string{"rot180"}},
+		{"glider@2.rle", "glider@2.rle", nil, nil},
+		{"glider@2.rle@1,2:flipy", "glider@2.rle", &Coord{1, 2}, []string{"flipy"}},
+		{`C:\patterns\glider.rle`, `C:\patterns\glider.rle`, nil, nil},
+		{`C:\patterns\glider.rle@3,4:rot270`, `C:\patterns\glider.rle`, &Coord{3, 4}, []string{"rot270"}},
+		{"dir:name/glider.rle", "dir:name/glider.rle", nil, nil},
+	}
+
+	for _, test := range tests {
+		p, err := parsePlacement(test.spec)
+		if err != nil {
+			t.Errorf("%q: %v", test.spec, err)
+			continue
+		}
+		if p.path != test.path || !reflect.DeepEqual(p.offset, test.offset) || !reflect.DeepEqual(p.transforms, test.transforms) {
+			t.Errorf("%q: got %q %v %v, want %q %v %v", test.spec, p.path, p.offset, p.transforms, test.path, test.offset, test.transforms)
+		}
+	}
+}
+
+func TestParsePlacementErrors(t *testing.T) {
+	for _, spec := range []string{"", "@1,2", "glider.cells:rot45", "glider.cells@1,2,3", "glider.cells@a,2"} {
+		if _, err := parsePlacement(spec); err == nil {
+			t.Errorf("%q: expected an error", spec)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nsf/termbox-go"
+)
+
+// ScreenCell is a single character on the screen with its colours and attributes.
+type ScreenCell struct {
+	Ch rune
+	Fg termbox.Attribute
+	Bg termbox.Attribute
+}
+
+// Screen is a frame of characters the UI draws into. It is copied to termbox
+// by Flush and can be rendered into ANSI escape sequences for recording
+// or for terminals connected over the network.
+type Screen struct {
+	Width  int
+	Height int
+	Cells  []ScreenCell
+}
+
+func NewScreen(width int, height int) *Screen {
+	s := &Screen{Width: max(width, 0), Height: max(height, 0)}
+	s.Cells = make([]ScreenCell, s.Width*s.Height)
+	s.Clear()
+	return s
+}
+
+func (s *Screen) Clear() {
+	for i := range s.Cells {
+		s.Cells[i] = ScreenCell{' ', termbox.ColorDefault, termbox.ColorDefault}
+	}
+}
+
+func (s *Screen) SetCell(x int, y int, ch rune, fg termbox.Attribute, bg termbox.Attribute) {
+	if x < 0 || x >= s.Width || y < 0 || y >= s.Height {
+		return
+	}
+	s.Cells[y*s.Width+x] = ScreenCell{ch, fg, bg}
+}
+
+func (s *Screen) Cell(x int, y int) ScreenCell {
+	return s.Cells[y*s.Width+x]
+}
+
+func (s *Screen) DrawString(x, y int, str string, fg, bg termbox.Attribute) {
+	for i, ch := range []rune(str) {
+		s.SetCell(x+i, y, ch, fg, bg)
+	}
+}
+
+// Flush copies the frame to termbox and shows it.
+func (s *Screen) Flush() error {
+	err := termbox.Clear(termbox.ColorDefault, termbox.ColorDefault)
+	if err != nil {
+		return err
+	}
+
+	mode := termbox.SetOutputMode(termbox.OutputCurrent)
+	for y := range s.Height {
+		for x := range s.Width {
+			c := s.Cell(x, y)
+			termbox.SetCell(x, y, c.Ch, outputAttribute(c.Fg, mode, true), outputAttribute(c.Bg, mode, false))
+		}
+	}
+
+	return termbox.Flush()
+}
+
+// ANSI renders the changes since the previous frame as ANSI escape sequences,
+// the whole frame is rendered when there is no previous frame of the same size.
+func (s *Screen) ANSI(previous *Screen, mode termbox.OutputMode) string {
+	var sb strings.Builder
+
+	full := previous == nil || previous.Width != s.Width || previous.Height != s.Height
+	if full {
+		sb.WriteString("\x1b[0m\x1b[2J")
+	}
+
+	var lastFg, lastBg termbox.Attribute
+	styled := false
+	cursor := Coord{-1, -1}
+	for y := range s.Height {
+		for x := range s.Width {
+			c := s.Cell(x, y)
+			if !full && previous.Cell(x, y) == c {
+				continue
+			}
+			if cursor != (Coord{x, y}) {
+				fmt.Fprintf(&sb, "\x1b[%d;%dH", y+1, x+1)
+			}
+			if !styled || c.Fg != lastFg || c.Bg != lastBg {
+				sb.WriteString(sgr(c.Fg, c.Bg, mode))
+				lastFg, lastBg, styled = c.Fg, c.Bg, true
+			}
+			sb.WriteRune(c.Ch)
+			cursor = Coord{x + 1, y}
+		}
+	}
+	sb.WriteString("\x1b[0m")
+
+	return sb.String()
+}
+
+// sgr returns the escape sequence selecting the colours the same way termbox does for the output mode.
+func sgr(fg termbox.Attribute, bg termbox.Attribute, mode termbox.OutputMode) string {
+	codes := []string{"0"}
+
+	attributes := []struct {
+		attr termbox.Attribute
+		code string
+	}{
+		{termbox.AttrBold, "1"},
+		{termbox.AttrDim, "2"},
+		{termbox.AttrCursive, "3"},
+		{termbox.AttrUnderline, "4"},
+		{termbox.AttrBlink, "5"},
+		{termbox.AttrReverse, "7"},
+		{termbox.AttrHidden, "8"},
+	}
+	for _, a := range attributes {
+		if fg&a.attr != 0 {
+			codes = append(codes, a.code)
+		}
+	}
+
+	if code := sgrColor(fg, mode, true); code != "" {
+		codes = append(codes, code)
+	}
+	if code := sgrColor(bg, mode, false); code != "" {
+		codes = append(codes, code)
+	}
+
+	return "\x1b[" + strings.Join(codes, ";") + "m"
+}
+
+func sgrColor(attr termbox.Attribute, mode termbox.OutputMode, foreground bool) string {
+	base := 3
+	if !foreground {
+		base = 4
+	}
+
+	switch mode {
+	case termbox.OutputRGB:
+		attr = outputAttribute(attr, mode, foreground)
+		if attr&^attributeFlags == termbox.ColorDefault {
+			return ""
+		}
+		r, g, b := termbox.AttributeToRGB(attr)
+		return fmt.Sprintf("%d8;2;%d;%d;%d", base, r, g, b)
+	case termbox.Output256:
+		color := attr & 0x1FF
+		if color == termbox.ColorDefault {
+			return ""
+		}
+		return fmt.Sprintf("%d8;5;%d", base, color-1)
+	default:
+		color := attr & 0xFF
+		if color == termbox.ColorDefault {
+			return ""
+		}
+		if color < termbox.ColorDarkGray {
+			return fmt.Sprintf("%d%d", base, color-termbox.ColorBlack)
+		}
+		return fmt.Sprintf("%d%d", base+6, color-termbox.ColorDarkGray)
+	}
+}
+
+const attributeFlags = termbox.AttrBold | termbox.AttrDim | termbox.AttrCursive | termbox.AttrUnderline |
+	termbox.AttrBlink | termbox.AttrReverse | termbox.AttrHidden
+
+// ansiColors are the xterm values of the standard colours from termbox.ColorBlack to termbox.ColorLightGray.
+var ansiColors = [][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0}, {0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0}, {92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// outputAttribute converts the standard colours to RGB values in the RGB output
+// mode, where termbox treats every colour as an RGB value. The default
+// foreground has no RGB value, white is used when it carries attributes.
+func outputAttribute(attr termbox.Attribute, mode termbox.OutputMode, foreground bool) termbox.Attribute {
+	if mode != termbox.OutputRGB || attr >= termbox.RGBToAttribute(0, 0, 0) {
+		return attr
+	}
+	color := attr &^ attributeFlags
+	if color == termbox.ColorDefault {
+		if attr == termbox.ColorDefault || !foreground {
+			return attr
+		}
+		color = termbox.ColorWhite
+	}
+	if int(color) > len(ansiColors) {
+		return attr
+	}
+	rgb := ansiColors[color-termbox.ColorBlack]
+	return termbox.RGBToAttribute(rgb[0], rgb[1], rgb[2]) | attr&attributeFlags
+}
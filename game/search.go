@@ -0,0 +1,247 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"math/rand"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	// SoupMaxGenerations limits the run of a soup that does not stabilise.
+	SoupMaxGenerations = 50000
+	// stabilityMaxPeriod is the longest population period of a stable soup.
+	stabilityMaxPeriod      = 30
+	stabilityMinGenerations = 100
+	searchProgressEvery     = 1000
+	// rareFindsRatio marks the objects seen in at most one of that many soups as rare.
+	rareFindsRatio = 1000
+)
+
+type soupResult struct {
+	seed        string
+	census      Census
+	generations int
+	stable      bool
+}
+
+type searchEntry struct {
+	CensusEntry
+	sample string
+}
+
+// runSearch runs the seeded random soups on all workers until they stabilise
+// and aggregates their censuses into the output file.
+func runSearch(parameters *UsageParameters) {
+	if *parameters.soupSize <= 0 || *parameters.workers <= 0 {
+		fmt.Printf("Invalid soup-size or workers specified: %d, %d\n", *parameters.soupSize, *parameters.workers)
+		os.Exit(3)
+	}
+	seed := *parameters.seed
+	if seed == "" {
+		seed = fmt.Sprintf("%x", rand.Uint32())
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	defer stop()
+
+	jobs := make(chan string)
+	results := make(chan soupResult)
+	var wg sync.WaitGroup
+	for range *parameters.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for soup := range jobs {
+				results <- searchSoup(soup, parameters)
+			}
+		}()
+	}
+	go func() {
+		defer close(jobs)
+		for i := range *parameters.search {
+			select {
+			case jobs <- soupSeed(seed, i):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	fmt.Printf("Searching %d soups of %dx%d cells with seed %s on %d workers\n",
+		*parameters.search, *parameters.soupSize, *parameters.soupSize, seed, *parameters.workers)
+
+	entries := make(map[string]*searchEntry)
+	soups, unstable := 0, 0
+	start := time.Now()
+	for result := range results {
+		soups++
+		if !result.stable {
+			unstable++
+		}
+		for _, e := range result.census.Entries {
+			if existing, ok := entries[e.Code]; ok {
+				existing.Count += e.Count
+			} else {
+				entries[e.Code] = &searchEntry{e, result.seed}
+			}
+		}
+		if soups%searchProgressEvery == 0 {
+			fmt.Printf("%d soups, %d objects, %.0f soups/s\n", soups, len(entries), float64(soups)/time.Since(start).Seconds())
+		}
+	}
+	if ctx.Err() != nil {
+		fmt.Printf("Search interrupted after %d soups\n", soups)
+	}
+
+	sorted := make([]searchEntry, 0, len(entries))
+	for _, e := range entries {
+		sorted = append(sorted, *e)
+	}
+	slices.SortFunc(sorted, func(a, b searchEntry) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Code, b.Code))
+	})
+
+	if err := writeSearchResults(*parameters.searchOutput, sorted, seed, soups, unstable, parameters); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Written %d objects of %d soups to %s\n", len(sorted), soups, *parameters.searchOutput)
+
+	rareLimit := max(1, soups/rareFindsRatio)
+	for _, e := range sorted {
+		if e.Count <= rareLimit {
+			fmt.Printf("Rare find: %s (%s, %d), reproduce with --soup %s --soup-size %d\n",
+				e.Code, e.Class, e.Count, e.sample, *parameters.soupSize)
+		}
+	}
+}
+
+func writeSearchResults(target string, entries []searchEntry, seed string, soups int, unstable int, parameters *UsageParameters) error {
+	file, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(file)
+	fmt.Fprintf(w, "# Seed: %s\n", seed)
+	fmt.Fprintf(w, "# Rule: %s\n", parameters.rule)
+	fmt.Fprintf(w, "# Soups: %d of %dx%d cells, %d did not stabilise in %d generations\n",
+		soups, *parameters.soupSize, *parameters.soupSize, unstable, SoupMaxGenerations)
+	fmt.Fprintf(w, "%10s  %-12s  %6s  %5s  %-24s  %s\n", "Count", "Class", "Period", "Cells", "Code", "Sample soup")
+	for _, e := range entries {
+		fmt.Fprintf(w, "%10d  %-12s  %6d  %5d  %-24s  %s\n", e.Count, e.Class, e.Period, e.Population, e.Code, e.sample)
+	}
+
+	err = w.Flush()
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func soupSeed(seed string, index int) string {
+	return fmt.Sprintf("%s_%d", seed, index)
+}
+
+// generateSoup returns the cells of the size*size soup, the same seed gives the same soup.
+func generateSoup(seed string, size int) []Coord {
+	h := fnv.New64a()
+	h.Write([]byte(seed))
+	rng := rand.New(rand.NewSource(int64(h.Sum64())))
+
+	var cells []Coord
+	for x := range size {
+		for y := range size {
+			if rng.Intn(2) == 1 {
+				cells = append(cells, Coord{x, y})
+			}
+		}
+	}
+	return cells
+}
+
+func searchSoup(seed string, parameters *UsageParameters) soupResult {
+	u := isolatedUniverse(*parameters)
+	for _, c := range generateSoup(seed, *parameters.soupSize) {
+		u.SetAliveCell(c.X, c.Y)
+	}
+
+	stable := runToStability(u, SoupMaxGenerations)
+	return soupResult{
+		seed:        seed,
+		census:      TakeCensus(u),
+		generations: u.Generation(),
+		stable:      stable,
+	}
+}
+
+// runToStability advances the universe until its population repeats with a short
+// period for a while, the way apgsearch detects the end of a soup.
+func runToStability(u Universe, maxGenerations int) bool {
+	var populations []int
+	for u.Generation() < maxGenerations {
+		populations = append(populations, u.AliveCount())
+		if u.AliveCount() == 0 {
+			return true
+		}
+		if len(populations) > stabilityMinGenerations && populationPeriod(populations) > 0 {
+			return true
+		}
+		u.NextStep()
+	}
+	return false
+}
+
+// populationPeriod returns the short period the latest populations repeat with, 0 when there is none.
+func populationPeriod(populations []int) int {
+	last := len(populations) - 1
+	for period := 1; period <= stabilityMaxPeriod; period++ {
+		window := 2*period + 20
+		if last-window-period < 0 {
+			break
+		}
+		periodic := true
+		for k := range window {
+			if populations[last-k] != populations[last-k-period] {
+				periodic = false
+				break
+			}
+		}
+		if periodic {
+			return period
+		}
+	}
+	return 0
+}
@@ -0,0 +1,486 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	maxStepsPerRequest = 100000
+	streamBufferSize   = 64
+	maxPatternSize     = 64 << 20
+)
+
+//go:embed web
+var webFiles embed.FS
+
+// LifeServer runs a single universe in the background and streams
+// per-generation cell diffs to the browser viewers.
+type LifeServer struct {
+	mu          sync.Mutex
+	game        *Game
+	parameters  *UsageParameters
+	width       int
+	height      int
+	paused      bool
+	alive       map[Coord]bool
+	seq         int
+	subscribers map[*subscriber]bool
+	speedCh     chan time.Duration
+	sessions    *SessionManager
+}
+
+// cellDiff is a stream event, a full event replaces every cell in the viewport.
+type cellDiff struct {
+	Seq        int      `json:"-"`
+	Generation int      `json:"generation"`
+	Population int      `json:"population"`
+	Full       bool     `json:"full"`
+	Born       [][2]int `json:"born"`
+	Died       [][2]int `json:"died"`
+}
+
+type subscriber struct {
+	viewport Bounds
+	events   chan cellDiff
+	resync   bool
+}
+
+type serverState struct {
+	Generation int     `json:"generation"`
+	Population int     `json:"population"`
+	Born       int     `json:"born"`
+	Died       int     `json:"died"`
+	Paused     bool    `json:"paused"`
+	Sleep      string  `json:"sleep"`
+	Rule       string  `json:"rule"`
+	BoardType  string  `json:"boardType"`
+	Title      string  `json:"title"`
+	Bounds     *Bounds `json:"bounds,omitempty"`
+}
+
+func NewLifeServer(parameters *UsageParameters) *LifeServer {
+	game := NewGame(parameters)
+	width, height := parameters.screenSize()
+
+	s := &LifeServer{
+		game:        &game,
+		parameters:  parameters,
+		width:       width,
+		height:      height,
+		subscribers: make(map[*subscriber]bool),
+		speedCh:     make(chan time.Duration, 1),
+		sessions:    NewSessionManager(parameters),
+	}
+	s.alive = s.aliveSet()
+
+	return s
+}
+
+// runServer serves the universe until SIGINT, SIGTERM or SIGHUP is received.
+func runServer(parameters *UsageParameters) {
+	s := NewLifeServer(parameters)
+	server := &http.Server{Addr: *parameters.serve, Handler: s.Handler()}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	defer stop()
+	go s.Run(ctx)
+
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdown)
+	}()
+
+	fmt.Printf("Serving the universe on http://%s\n", *parameters.serve)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+}
+
+func (s *LifeServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	web, err := fs.Sub(webFiles, "web")
+	if err != nil {
+		panic(err)
+	}
+	mux.Handle("GET /", http.FileServerFS(web))
+	mux.HandleFunc("GET /api/state", s.handleState)
+	mux.HandleFunc("GET /api/stream", s.handleStream)
+	mux.HandleFunc("POST /api/pause", s.handlePause)
+	mux.HandleFunc("POST /api/resume", s.handleResume)
+	mux.HandleFunc("POST /api/step", s.handleStep)
+	mux.HandleFunc("POST /api/speed", s.handleSpeed)
+	mux.HandleFunc("POST /api/load", s.handleLoad)
+	s.sessions.Register(mux)
+
+	return mux
+}
+
+// Run advances the universe every sleep interval until the context is done.
+func (s *LifeServer) Run(ctx context.Context) {
+	tick := time.NewTicker(*s.parameters.sleep)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case sleep := <-s.speedCh:
+			tick.Reset(sleep)
+		case <-tick.C:
+			s.mu.Lock()
+			if !s.paused {
+				s.step(1)
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// step advances the universe and publishes the difference, the caller holds the lock.
+func (s *LifeServer) step(n int) {
+	u := s.game.Universe
+	for range n {
+		if u.AliveCount() == 0 || s.parameters.gensReached(u.Generation()) {
+			break
+		}
+		u.NextStep()
+	}
+	s.publish()
+}
+
+func (s *LifeServer) aliveSet() map[Coord]bool {
+	alive := make(map[Coord]bool, s.game.Universe.AliveCount())
+	s.game.Universe.ForEachAlive(func(cell Coord, age int) {
+		alive[cell] = true
+	})
+	return alive
+}
+
+// publish sends the cells changed since the previous publish to every subscriber,
+// slow subscribers get a full resync instead of the lost events.
+func (s *LifeServer) publish() {
+	alive := s.aliveSet()
+	var born, died []Coord
+	for c := range alive {
+		if !s.alive[c] {
+			born = append(born, c)
+		}
+	}
+	for c := range s.alive {
+		if !alive[c] {
+			died = append(died, c)
+		}
+	}
+	s.alive = alive
+	s.seq++
+
+	for sub := range s.subscribers {
+		diff := s.newDiff(false)
+		diff.Born = inViewport(born, sub.viewport)
+		diff.Died = inViewport(died, sub.viewport)
+
+		select {
+		case sub.events <- diff:
+		default:
+			sub.resync = true
+		}
+	}
+}
+
+func (s *LifeServer) newDiff(full bool) cellDiff {
+	return cellDiff{
+		Seq:        s.seq,
+		Generation: s.game.Universe.Generation(),
+		Population: s.game.Universe.AliveCount(),
+		Full:       full,
+		Born:       [][2]int{},
+		Died:       [][2]int{},
+	}
+}
+
+// fullDiff returns every alive cell in the viewport, the caller holds the lock.
+func (s *LifeServer) fullDiff(viewport Bounds) cellDiff {
+	diff := s.newDiff(true)
+	cells := make([]Coord, 0, len(s.alive))
+	for c := range s.alive {
+		cells = append(cells, c)
+	}
+	diff.Born = inViewport(cells, viewport)
+	return diff
+}
+
+func inViewport(cells []Coord, viewport Bounds) [][2]int {
+	result := [][2]int{}
+	for _, c := range cells {
+		if c.X >= viewport.TopLeft.X && c.X <= viewport.BottomRight.X &&
+			c.Y >= viewport.TopLeft.Y && c.Y <= viewport.BottomRight.Y {
+			result = append(result, [2]int{c.X, c.Y})
+		}
+	}
+	return result
+}
+
+func (s *LifeServer) state() serverState {
+	u := s.game.Universe
+	stats := u.Stats()[u.Generation()]
+	state := serverState{
+		Generation: u.Generation(),
+		Population: u.AliveCount(),
+		Born:       stats.born,
+		Died:       stats.died,
+		Paused:     s.paused,
+		Sleep:      s.parameters.sleep.String(),
+		Rule:       s.parameters.rule.String(),
+		BoardType:  *s.parameters.boardType,
+		Title:      s.game.Metadata.Title(),
+	}
+	if bounds := u.GameBounds(); !bounds.isEmpty() {
+		state.Bounds = &bounds
+	}
+	return state
+}
+
+func (s *LifeServer) handleState(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	state := s.state()
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, state)
+}
+
+// handleStream sends Server-Sent Events with the cell diffs inside the viewport
+// given by x, y, w and h query parameters. The first event is a full one.
+func (s *LifeServer) handleStream(w http.ResponseWriter, r *http.Request) {
+	viewport, err := parseViewport(fmt.Sprintf("%s,%s,%s,%s",
+		r.URL.Query().Get("x"), r.URL.Query().Get("y"), r.URL.Query().Get("w"), r.URL.Query().Get("h")))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	sub := &subscriber{viewport: viewport, events: make(chan cellDiff, streamBufferSize)}
+	s.mu.Lock()
+	s.subscribers[sub] = true
+	last := s.fullDiff(viewport)
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.subscribers, sub)
+		s.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	diff := last
+	for {
+		if err := writeEvent(w, diff); err != nil {
+			return
+		}
+		flusher.Flush()
+
+		select {
+		case <-r.Context().Done():
+			return
+		case diff = <-sub.events:
+		}
+
+		s.mu.Lock()
+		if sub.resync {
+			sub.resync = false
+			last = s.fullDiff(viewport)
+			diff = last
+		}
+		s.mu.Unlock()
+
+		// Events queued before the last full resync are already included in it.
+		for diff.Seq <= last.Seq && !diff.Full {
+			select {
+			case <-r.Context().Done():
+				return
+			case diff = <-sub.events:
+			}
+		}
+	}
+}
+
+func writeEvent(w io.Writer, diff cellDiff) error {
+	data, err := json.Marshal(diff)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "data: %s\n\n", data)
+	return err
+}
+
+func (s *LifeServer) handlePause(w http.ResponseWriter, r *http.Request) {
+	s.setPaused(w, true)
+}
+
+func (s *LifeServer) handleResume(w http.ResponseWriter, r *http.Request) {
+	s.setPaused(w, false)
+}
+
+func (s *LifeServer) setPaused(w http.ResponseWriter, paused bool) {
+	s.mu.Lock()
+	s.paused = paused
+	state := s.state()
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, state)
+}
+
+// handleStep advances the universe by n generations, n defaults to 1.
+func (s *LifeServer) handleStep(w http.ResponseWriter, r *http.Request) {
+	n := 1
+	if value := r.URL.Query().Get("n"); value != "" {
+		var err error
+		n, err = strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxStepsPerRequest {
+			http.Error(w, fmt.Sprintf("invalid n %q, expected 1-%d", value, maxStepsPerRequest), http.StatusBadRequest)
+			return
+		}
+	}
+
+	s.mu.Lock()
+	s.step(n)
+	state := s.state()
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, state)
+}
+
+// handleSpeed sets the interval between generations from the sleep query parameter, e.g. 50ms.
+func (s *LifeServer) handleSpeed(w http.ResponseWriter, r *http.Request) {
+	sleep, err := time.ParseDuration(r.URL.Query().Get("sleep"))
+	if err != nil || sleep < SpeedIncrement {
+		http.Error(w, fmt.Sprintf("invalid sleep %q, expected a duration of at least %s", r.URL.Query().Get("sleep"), SpeedIncrement), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	*s.parameters.sleep = sleep
+	state := s.state()
+	s.mu.Unlock()
+
+	select {
+	case s.speedCh <- sleep:
+	default:
+		<-s.speedCh
+		s.speedCh <- sleep
+	}
+	writeJSON(w, http.StatusOK, state)
+}
+
+// handleLoad puts the pattern from the request body into the universe. The format
+// query parameter selects the pattern format, x and y the position of the top left
+// corner, without them the pattern is centered on the board. With clear=true
+// the universe is emptied first.
+func (s *LifeServer) handleLoad(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format == "" {
+		format = FormatRLE
+	}
+
+	// The pattern is parsed and placed before taking the lock, patterns larger than
+	// the board are rejected before their cells are allocated.
+	limit := boardLimit(*s.parameters.boardType, s.width, s.height)
+	pattern, err := parsePatternWithin(http.MaxBytesReader(w, r.Body, maxPatternSize), format, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var position Coord
+	if query.Has("x") || query.Has("y") {
+		x, errX := strconv.Atoi(query.Get("x"))
+		y, errY := strconv.Atoi(query.Get("y"))
+		if errX != nil || errY != nil {
+			http.Error(w, "invalid x or y", http.StatusBadRequest)
+			return
+		}
+		position = Coord{x, y}
+	} else if pattern.Origin != nil {
+		position = *pattern.Origin
+	} else {
+		width, height := pattern.size()
+		position = Coord{(s.width - width) / 2, (s.height - height) / 2}
+	}
+	var cells []Coord
+	pattern.forEachAlive(func(x int, y int) {
+		cells = append(cells, Coord{position.X + x, position.Y + y})
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if query.Get("clear") == "true" {
+		u, err := createUniverse(*s.parameters.boardType, s.width, s.height, s.parameters)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		s.game.Universe = u
+		s.game.Metadata = PatternMetadata{}
+	}
+
+	s.game.Metadata = s.game.Metadata.merge(pattern.Metadata)
+	for _, cell := range cells {
+		s.game.Universe.SetAliveCell(cell.X, cell.Y)
+	}
+	s.publish()
+
+	writeJSON(w, http.StatusOK, s.state())
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
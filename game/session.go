@@ -0,0 +1,186 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+const (
+	SnapshotFormat  = "go-life-snapshot"
+	SnapshotVersion = 1
+)
+
+// snapshot is a full game session, it is stored as gzip compressed JSON.
+type snapshot struct {
+	Format     string                `json:"format"`
+	Version    int                   `json:"version"`
+	BoardType  string                `json:"boardType"`
+	Width      int                   `json:"width,omitempty"`
+	Height     int                   `json:"height,omitempty"`
+	Rule       string                `json:"rule"`
+	Generation int                   `json:"generation"`
+	Cells      [][3]int              `json:"cells"`
+	Stats      map[int]snapshotStats `json:"stats"`
+	Origin     Coord                 `json:"origin"`
+	Sleep      time.Duration         `json:"sleep"`
+	Metadata   PatternMetadata       `json:"metadata"`
+}
+
+type snapshotStats struct {
+	Alive int `json:"alive"`
+	Born  int `json:"born"`
+	Dead  int `json:"dead"`
+	Died  int `json:"died"`
+}
+
+// SaveSnapshot writes the whole session: the universe with cell ages, statistics,
+// rule, topology, origin and the sleep interval.
+func (game *Game) SaveSnapshot(target string, parameters *UsageParameters) error {
+	file, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	err = game.WriteSnapshot(file, *parameters.sleep)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// WriteSnapshot writes the session as gzip compressed JSON to the writer.
+func (game *Game) WriteSnapshot(w io.Writer, sleep time.Duration) error {
+	u := game.Universe
+
+	s := snapshot{
+		Format:     SnapshotFormat,
+		Version:    SnapshotVersion,
+		BoardType:  *u.Parameters().boardType,
+		Rule:       u.Parameters().rule.String(),
+		Generation: u.Generation(),
+		Stats:      make(map[int]snapshotStats),
+		Origin:     game.Origin,
+		Sleep:      sleep,
+		Metadata:   game.Metadata,
+	}
+	if s.BoardType == "boarded" {
+		bounds := u.GameBounds()
+		s.Width = bounds.BottomRight.X - bounds.TopLeft.X + 1
+		s.Height = bounds.BottomRight.Y - bounds.TopLeft.Y + 1
+	}
+	u.ForEachAlive(func(cell Coord, age int) {
+		s.Cells = append(s.Cells, [3]int{cell.X, cell.Y, age})
+	})
+	// Only the statistics of the current generation are shown, so the history
+	// is not stored and checkpoints of long runs do not grow with it.
+	if stats, ok := u.Stats()[u.Generation()]; ok {
+		s.Stats[u.Generation()] = snapshotStats{stats.alive, stats.born, stats.dead, stats.died}
+	}
+
+	zw := gzip.NewWriter(w)
+	err := json.NewEncoder(zw).Encode(s)
+	if closeErr := zw.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func readSnapshot(source string) (snapshot, error) {
+	var s snapshot
+
+	file, err := os.Open(source)
+	if err != nil {
+		return s, err
+	}
+	defer file.Close()
+
+	zr, err := gzip.NewReader(file)
+	if err != nil {
+		return s, fmt.Errorf("%s is not a snapshot: %w", source, err)
+	}
+	defer zr.Close()
+
+	if err := json.NewDecoder(zr).Decode(&s); err != nil {
+		return s, fmt.Errorf("%s is not a snapshot: %w", source, err)
+	}
+	if s.Format != SnapshotFormat {
+		return s, fmt.Errorf("%s is not a snapshot", source)
+	}
+	if s.Version > SnapshotVersion {
+		return s, fmt.Errorf("%s has snapshot version %d, only versions up to %d are supported", source, s.Version, SnapshotVersion)
+	}
+
+	return s, nil
+}
+
+// restoreGame continues the session from the snapshot, the rule, board type
+// and sleep interval of the snapshot override the parameters.
+// When the source is a directory the newest checkpoint in it is used.
+func restoreGame(source string, parameters *UsageParameters) (Game, error) {
+	source, err := resolveSnapshot(source)
+	if err != nil {
+		return Game{}, err
+	}
+
+	s, err := readSnapshot(source)
+	if err != nil {
+		return Game{}, err
+	}
+
+	rule, err := ParseRule(s.Rule)
+	if err != nil {
+		return Game{}, err
+	}
+	if s.BoardType == "boarded" && (s.Width <= 0 || s.Height <= 0) {
+		return Game{}, fmt.Errorf("%s: invalid board size %dx%d", source, s.Width, s.Height)
+	}
+	if s.Sleep <= 0 {
+		return Game{}, fmt.Errorf("%s: invalid sleep %v", source, s.Sleep)
+	}
+	parameters.rule = rule
+	*parameters.boardType = s.BoardType
+	*parameters.sleep = s.Sleep
+
+	u, err := createUniverse(s.BoardType, s.Width, s.Height, parameters)
+	if err != nil {
+		return Game{}, fmt.Errorf("%s: %w", source, err)
+	}
+
+	cells := make(map[Coord]int, len(s.Cells))
+	for _, c := range s.Cells {
+		cells[Coord{c[0], c[1]}] = c[2]
+	}
+	stats := make(map[int]UniverseStats, len(s.Stats))
+	for generation, st := range s.Stats {
+		stats[generation] = UniverseStats{st.Alive, st.Born, st.Dead, st.Died}
+	}
+	u.Restore(s.Generation, cells, stats)
+
+	return Game{
+		Universe: u,
+		Origin:   s.Origin,
+		Metadata: s.Metadata,
+	}, nil
+}
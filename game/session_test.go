@@ -0,0 +1,156 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeTestSnapshot(t *testing.T, s snapshot) string {
+	t.Helper()
+
+	target := filepath.Join(t.TempDir(), "session.life")
+	file, err := os.Create(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	zw := gzip.NewWriter(file)
+	if err := json.NewEncoder(zw).Encode(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return target
+}
+
+func testParameters() *UsageParameters {
+	boardType := "infinite"
+	sleep := time.Second
+	return &UsageParameters{boardType: &boardType, sleep: &sleep, rule: ConwayRule}
+}
+
+func TestRestoreGame(t *testing.T) {
+	source := writeTestSnapshot(t, snapshot{
+		Format:     SnapshotFormat,
+		Version:    SnapshotVersion,
+		BoardType:  "boarded",
+		Width:      10,
+		Height:     8,
+		Rule:       "B36/S23",
+		Generation: 42,
+		Cells:      [][3]int{{1, 2, 1}, {3, 4, 5}},
+		Origin:     Coord{-3, 2},
+		Sleep:      250 * time.Millisecond,
+	})
+
+	parameters := testParameters()
+	game, err := restoreGame(source, parameters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := game.Universe.Generation(); got != 42 {
+		t.Errorf("got generation %d, want 42", got)
+	}
+	if game.Origin != (Coord{-3, 2}) {
+		t.Errorf("got origin %v, want {-3 2}", game.Origin)
+	}
+	if *parameters.boardType != "boarded" || *parameters.sleep != 250*time.Millisecond {
+		t.Errorf("got board %s and sleep %v", *parameters.boardType, *parameters.sleep)
+	}
+	if got := game.Universe.Parameters().rule.String(); got != "B36/S23" {
+		t.Errorf("got rule %s, want B36/S23", got)
+	}
+}
+
+func TestRestoreGameErrors(t *testing.T) {
+	valid := snapshot{
+		Format:    SnapshotFormat,
+		Version:   SnapshotVersion,
+		BoardType: "boarded",
+		Width:     10,
+		Height:    10,
+		Rule:      "B3/S23",
+		Sleep:     time.Second,
+	}
+
+	tests := []struct {
+		name   string
+		change func(s *snapshot)
+		want   string
+	}{
+		{"zero width", func(s *snapshot) { s.Width = 0 }, "invalid board size"},
+		{"negative height", func(s *snapshot) { s.Height = -5 }, "invalid board size"},
+		{"zero sleep", func(s *snapshot) { s.Sleep = 0 }, "invalid sleep"},
+		{"negative sleep", func(s *snapshot) { s.Sleep = -time.Second }, "invalid sleep"},
+		{"invalid rule", func(s *snapshot) { s.Rule = "Life" }, "invalid rule"},
+		{"unknown board", func(s *snapshot) { s.BoardType = "torus" }, "unknown board type"},
+		{"newer version", func(s *snapshot) { s.Version = SnapshotVersion + 1 }, "snapshot version"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := valid
+			test.change(&s)
+			_, err := restoreGame(writeTestSnapshot(t, s), testParameters())
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("got error %v, want %q", err, test.want)
+			}
+		})
+	}
+}
+
+func TestWriteSnapshotKeepsCurrentStats(t *testing.T) {
+	parameters := testParameters()
+	game := Game{Universe: CreateUniverseInfinite(parameters)}
+	game.embedMatrixAt(fromRows("oo.", ".oo", ".o."), 0, 0)
+	for range 50 {
+		game.Universe.NextStep()
+	}
+
+	source := filepath.Join(t.TempDir(), "session.snapshot")
+	if err := game.SaveSnapshot(source, parameters); err != nil {
+		t.Fatal(err)
+	}
+	s, err := readSnapshot(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := game.Universe.Stats()[50]
+	if len(s.Stats) != 1 || s.Stats[50] != (snapshotStats{want.alive, want.born, want.dead, want.died}) {
+		t.Errorf("got stats %v, want only generation 50 %+v", s.Stats, want)
+	}
+
+	restored, err := restoreGame(source, testParameters())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := restored.Universe.Stats()[50]; got != want {
+		t.Errorf("got restored stats %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,379 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	maxSessionBoardSize = 4096
+	maxSessions         = 64
+)
+
+// SessionManager serves the REST API of independent simulation sessions,
+// every session has its own universe, rule and lock.
+type SessionManager struct {
+	mu         sync.Mutex
+	sessions   map[string]*apiSession
+	lastID     int
+	parameters *UsageParameters
+}
+
+type apiSession struct {
+	mu         sync.Mutex
+	id         string
+	game       Game
+	parameters *UsageParameters
+	width      int
+	height     int
+	created    time.Time
+}
+
+// sessionRequest creates a session. Without a pattern the board of width
+// and height is filled randomly with the population percentage of cells.
+type sessionRequest struct {
+	Rule       string `json:"rule"`
+	BoardType  string `json:"boardType"`
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+	Pattern    string `json:"pattern"`
+	Format     string `json:"format"`
+	X          *int   `json:"x"`
+	Y          *int   `json:"y"`
+	Population int    `json:"population"`
+}
+
+type sessionInfo struct {
+	ID         string    `json:"id"`
+	Rule       string    `json:"rule"`
+	BoardType  string    `json:"boardType"`
+	Width      int       `json:"width,omitempty"`
+	Height     int       `json:"height,omitempty"`
+	Title      string    `json:"title"`
+	Generation int       `json:"generation"`
+	Population int       `json:"population"`
+	Born       int       `json:"born"`
+	Died       int       `json:"died"`
+	Bounds     *Bounds   `json:"bounds,omitempty"`
+	Created    time.Time `json:"created"`
+}
+
+type sessionCells struct {
+	Generation int      `json:"generation"`
+	Cells      [][3]int `json:"cells"`
+}
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+func NewSessionManager(parameters *UsageParameters) *SessionManager {
+	return &SessionManager{
+		sessions:   make(map[string]*apiSession),
+		parameters: parameters,
+	}
+}
+
+// Register adds the session routes to the mux.
+func (m *SessionManager) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/sessions", m.handleList)
+	mux.HandleFunc("POST /api/sessions", m.handleCreate)
+	mux.HandleFunc("GET /api/sessions/{id}", m.withSession(m.handleInfo))
+	mux.HandleFunc("DELETE /api/sessions/{id}", m.handleDelete)
+	mux.HandleFunc("POST /api/sessions/{id}/step", m.withSession(m.handleStep))
+	mux.HandleFunc("GET /api/sessions/{id}/cells", m.withSession(m.handleCells))
+	mux.HandleFunc("GET /api/sessions/{id}/snapshot", m.withSession(m.handleSnapshot))
+}
+
+func (m *SessionManager) Handler() http.Handler {
+	mux := http.NewServeMux()
+	m.Register(mux)
+	return mux
+}
+
+// withSession looks the session up and holds its lock while the handler runs.
+func (m *SessionManager) withSession(handler func(http.ResponseWriter, *http.Request, *apiSession)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		session, ok := m.sessions[r.PathValue("id")]
+		m.mu.Unlock()
+		if !ok {
+			writeError(w, http.StatusNotFound, fmt.Errorf("session %q not found", r.PathValue("id")))
+			return
+		}
+
+		session.mu.Lock()
+		defer session.mu.Unlock()
+		handler(w, r, session)
+	}
+}
+
+func (m *SessionManager) handleList(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	sessions := make([]*apiSession, 0, len(m.sessions))
+	for _, session := range m.sessions {
+		sessions = append(sessions, session)
+	}
+	m.mu.Unlock()
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].created.Before(sessions[j].created)
+	})
+	infos := make([]sessionInfo, len(sessions))
+	for i, session := range sessions {
+		session.mu.Lock()
+		infos[i] = session.info()
+		session.mu.Unlock()
+	}
+
+	writeJSON(w, http.StatusOK, infos)
+}
+
+func (m *SessionManager) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var request sessionRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxPatternSize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid session request: %w", err))
+		return
+	}
+
+	session, err := m.newSession(request)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// The info is taken before the session is published, other requests
+	// may lock and step it right after.
+	m.mu.Lock()
+	if len(m.sessions) >= maxSessions {
+		m.mu.Unlock()
+		writeError(w, http.StatusServiceUnavailable, fmt.Errorf("too many sessions, at most %d are allowed", maxSessions))
+		return
+	}
+	m.lastID++
+	session.id = strconv.Itoa(m.lastID)
+	info := session.info()
+	m.sessions[session.id] = session
+	m.mu.Unlock()
+
+	writeJSON(w, http.StatusCreated, info)
+}
+
+func (m *SessionManager) newSession(request sessionRequest) (*apiSession, error) {
+	parameters := *m.parameters
+	boardType := request.BoardType
+	if boardType == "" {
+		boardType = "infinite"
+	}
+	parameters.boardType = &boardType
+
+	parameters.rule = ConwayRule
+	if request.Rule != "" {
+		rule, err := ParseRule(request.Rule)
+		if err != nil {
+			return nil, err
+		}
+		parameters.rule = rule
+	}
+
+	if request.Width < 0 || request.Height < 0 || request.Width > maxSessionBoardSize || request.Height > maxSessionBoardSize {
+		return nil, fmt.Errorf("invalid board size %dx%d, width and height must be 0-%d", request.Width, request.Height, maxSessionBoardSize)
+	}
+	if boardType == "boarded" && (request.Width == 0 || request.Height == 0) {
+		return nil, fmt.Errorf("width and height are required for the boarded board")
+	}
+	if request.Population < 0 || request.Population > 100 {
+		return nil, fmt.Errorf("invalid population %d, expected 0-100", request.Population)
+	}
+
+	var pattern *Pattern
+	if request.Pattern != "" {
+		format := request.Format
+		if format == "" {
+			format = FormatRLE
+		}
+		// Patterns larger than the board are rejected before their cells are allocated.
+		p, err := parsePatternWithin(strings.NewReader(request.Pattern), format, boardLimit(boardType, request.Width, request.Height))
+		if err != nil {
+			return nil, err
+		}
+		if request.Rule == "" && p.Rule != "" {
+			if rule, err := ParseRule(p.Rule); err == nil {
+				parameters.rule = rule
+			}
+		}
+		pattern = &p
+	}
+
+	u, err := createUniverse(boardType, request.Width, request.Height, &parameters)
+	if err != nil {
+		return nil, err
+	}
+	session := &apiSession{
+		game:       Game{Universe: u},
+		parameters: &parameters,
+		width:      request.Width,
+		height:     request.Height,
+		created:    time.Now(),
+	}
+
+	if pattern == nil {
+		for i := range request.Width {
+			for j := range request.Height {
+				if rand.Intn(100) < request.Population {
+					u.SetAliveCell(i, j)
+				}
+			}
+		}
+		return session, nil
+	}
+
+	session.game.Metadata = pattern.Metadata
+	if request.X != nil || request.Y != nil {
+		session.game.embedPatternAt(*pattern, valueOrZero(request.X), valueOrZero(request.Y))
+	} else if pattern.Origin != nil {
+		session.game.embedPatternAt(*pattern, pattern.Origin.X, pattern.Origin.Y)
+	} else {
+		width, height := pattern.size()
+		session.game.embedPatternAt(*pattern, (request.Width-width)/2, (request.Height-height)/2)
+	}
+
+	return session, nil
+}
+
+func valueOrZero(value *int) int {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
+
+func (m *SessionManager) handleDelete(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	_, ok := m.sessions[r.PathValue("id")]
+	delete(m.sessions, r.PathValue("id"))
+	m.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("session %q not found", r.PathValue("id")))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *SessionManager) handleInfo(w http.ResponseWriter, r *http.Request, session *apiSession) {
+	writeJSON(w, http.StatusOK, session.info())
+}
+
+// handleStep advances the session by n generations, n defaults to 1.
+func (m *SessionManager) handleStep(w http.ResponseWriter, r *http.Request, session *apiSession) {
+	n := 1
+	if value := r.URL.Query().Get("n"); value != "" {
+		var err error
+		n, err = strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxStepsPerRequest {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid n %q, expected 1-%d", value, maxStepsPerRequest))
+			return
+		}
+	}
+
+	for range n {
+		session.game.Universe.NextStep()
+	}
+	writeJSON(w, http.StatusOK, session.info())
+}
+
+// handleCells returns the alive cells with their ages in the rectangle given
+// by x, y, w and h query parameters, the whole universe without them.
+func (m *SessionManager) handleCells(w http.ResponseWriter, r *http.Request, session *apiSession) {
+	query := r.URL.Query()
+	viewport := session.game.Universe.GameBounds()
+	if query.Has("x") || query.Has("y") || query.Has("w") || query.Has("h") {
+		var err error
+		viewport, err = parseViewport(fmt.Sprintf("%s,%s,%s,%s", query.Get("x"), query.Get("y"), query.Get("w"), query.Get("h")))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	result := sessionCells{
+		Generation: session.game.Universe.Generation(),
+		Cells:      [][3]int{},
+	}
+	session.game.Universe.ForEachAlive(func(c Coord, age int) {
+		if c.X >= viewport.TopLeft.X && c.X <= viewport.BottomRight.X &&
+			c.Y >= viewport.TopLeft.Y && c.Y <= viewport.BottomRight.Y {
+			result.Cells = append(result.Cells, [3]int{c.X, c.Y, age})
+		}
+	})
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleSnapshot returns the session in the snapshot format of the 's' key,
+// it can be continued with --resume.
+func (m *SessionManager) handleSnapshot(w http.ResponseWriter, r *http.Request, session *apiSession) {
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"session-%s.snapshot\"", session.id))
+	// The response is already streamed, the error can only be logged.
+	if err := session.game.WriteSnapshot(w, *session.parameters.sleep); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+func (s *apiSession) info() sessionInfo {
+	u := s.game.Universe
+	stats := u.Stats()[u.Generation()]
+	info := sessionInfo{
+		ID:         s.id,
+		Rule:       s.parameters.rule.String(),
+		BoardType:  *s.parameters.boardType,
+		Title:      s.game.Metadata.Title(),
+		Generation: u.Generation(),
+		Population: u.AliveCount(),
+		Born:       stats.born,
+		Died:       stats.died,
+		Created:    s.created,
+	}
+	if info.BoardType == "boarded" {
+		info.Width, info.Height = s.width, s.height
+	}
+	if bounds := u.GameBounds(); !bounds.isEmpty() {
+		info.Bounds = &bounds
+	}
+	return info
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, apiError{err.Error()})
+}
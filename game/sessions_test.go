@@ -0,0 +1,202 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const gliderRequest = `{"pattern": "x = 3, y = 3\nbo$2bo$3o!"}`
+
+func request(t *testing.T, handler http.Handler, method string, path string, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return recorder
+}
+
+func decodeInfo(t *testing.T, recorder *httptest.ResponseRecorder) sessionInfo {
+	t.Helper()
+	var info sessionInfo
+	if err := json.NewDecoder(recorder.Body).Decode(&info); err != nil {
+		t.Fatal(err)
+	}
+	return info
+}
+
+func TestSessionCreate(t *testing.T) {
+	handler := NewSessionManager(testParameters()).Handler()
+
+	recorder := request(t, handler, "POST", "/api/sessions", `{"rule": "B36/S23", "boardType": "boarded", "width": 20, "height": 10, "pattern": "x = 3, y = 3\nbo$2bo$3o!"}`)
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("got status %d: %s", recorder.Code, recorder.Body)
+	}
+	info := decodeInfo(t, recorder)
+	if info.ID != "1" || info.Rule != "B36/S23" || info.BoardType != "boarded" || info.Width != 20 || info.Height != 10 {
+		t.Errorf("unexpected session %+v", info)
+	}
+	if info.Generation != 0 || info.Population != 5 {
+		t.Errorf("got generation %d and population %d, want 0 and 5", info.Generation, info.Population)
+	}
+
+	recorder = request(t, handler, "GET", "/api/sessions/1", "")
+	if recorder.Code != http.StatusOK || decodeInfo(t, recorder).Population != 5 {
+		t.Errorf("got status %d for the created session", recorder.Code)
+	}
+}
+
+func TestSessionCreateErrors(t *testing.T) {
+	handler := NewSessionManager(testParameters()).Handler()
+
+	for _, body := range []string{
+		`{"rule":`,
+		`{"unknown": 1}`,
+		`{"rule": "Life"}`,
+		`{"boardType": "torus"}`,
+		`{"boardType": "boarded"}`,
+		`{"width": -1, "height": 10}`,
+		`{"width": 100000, "height": 10}`,
+		`{"width": 10, "height": 10, "population": 101}`,
+		`{"pattern": "o?o!"}`,
+		`{"pattern": "x = 100000, y = 100000\no!"}`,
+		`{"boardType": "boarded", "width": 10, "height": 10, "pattern": "x = 100, y = 100\no!"}`,
+		`{"boardType": "boarded", "width": 10, "height": 10, "pattern": "20bo!"}`,
+	} {
+		recorder := request(t, handler, "POST", "/api/sessions", body)
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("%s: got status %d, want %d", body, recorder.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestSessionLimit(t *testing.T) {
+	handler := NewSessionManager(testParameters()).Handler()
+
+	for range maxSessions {
+		if recorder := request(t, handler, "POST", "/api/sessions", gliderRequest); recorder.Code != http.StatusCreated {
+			t.Fatalf("got status %d: %s", recorder.Code, recorder.Body)
+		}
+	}
+	if recorder := request(t, handler, "POST", "/api/sessions", gliderRequest); recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("got status %d, want %d", recorder.Code, http.StatusServiceUnavailable)
+	}
+
+	request(t, handler, "DELETE", "/api/sessions/1", "")
+	if recorder := request(t, handler, "POST", "/api/sessions", gliderRequest); recorder.Code != http.StatusCreated {
+		t.Errorf("got status %d after a session was deleted", recorder.Code)
+	}
+}
+
+func TestSessionList(t *testing.T) {
+	handler := NewSessionManager(testParameters()).Handler()
+
+	recorder := request(t, handler, "GET", "/api/sessions", "")
+	if recorder.Code != http.StatusOK || strings.TrimSpace(recorder.Body.String()) != "[]" {
+		t.Errorf("got status %d and %q for no sessions", recorder.Code, recorder.Body)
+	}
+
+	request(t, handler, "POST", "/api/sessions", gliderRequest)
+	request(t, handler, "POST", "/api/sessions", `{"rule": "B36/S23"}`)
+
+	recorder = request(t, handler, "GET", "/api/sessions", "")
+	var infos []sessionInfo
+	if err := json.NewDecoder(recorder.Body).Decode(&infos); err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 2 || infos[0].ID != "1" || infos[1].ID != "2" || infos[1].Rule != "B36/S23" {
+		t.Errorf("unexpected sessions %+v", infos)
+	}
+}
+
+func TestSessionStep(t *testing.T) {
+	handler := NewSessionManager(testParameters()).Handler()
+	request(t, handler, "POST", "/api/sessions", gliderRequest)
+
+	recorder := request(t, handler, "POST", "/api/sessions/1/step", "")
+	if info := decodeInfo(t, recorder); recorder.Code != http.StatusOK || info.Generation != 1 {
+		t.Errorf("got status %d and generation %d, want 1", recorder.Code, info.Generation)
+	}
+
+	recorder = request(t, handler, "POST", "/api/sessions/1/step?n=3", "")
+	info := decodeInfo(t, recorder)
+	if info.Generation != 4 || info.Population != 5 {
+		t.Errorf("got generation %d and population %d, want 4 and 5", info.Generation, info.Population)
+	}
+	// The glider starts centered at {-1 -1} and moves by one cell diagonally every 4 generations.
+	if info.Bounds == nil || info.Bounds.TopLeft != (Coord{0, 0}) {
+		t.Errorf("got bounds %v, want the top left at {0 0}", info.Bounds)
+	}
+
+	for _, n := range []string{"0", "-1", "x", "100001"} {
+		if recorder := request(t, handler, "POST", "/api/sessions/1/step?n="+n, ""); recorder.Code != http.StatusBadRequest {
+			t.Errorf("n=%s: got status %d, want %d", n, recorder.Code, http.StatusBadRequest)
+		}
+	}
+	if recorder := request(t, handler, "POST", "/api/sessions/2/step", ""); recorder.Code != http.StatusNotFound {
+		t.Errorf("got status %d for an unknown session, want %d", recorder.Code, http.StatusNotFound)
+	}
+}
+
+func TestSessionSnapshot(t *testing.T) {
+	handler := NewSessionManager(testParameters()).Handler()
+	request(t, handler, "POST", "/api/sessions", `{"rule": "B36/S23", "pattern": "x = 3, y = 3\nbo$2bo$3o!"}`)
+	request(t, handler, "POST", "/api/sessions/1/step?n=8", "")
+
+	recorder := request(t, handler, "GET", "/api/sessions/1/snapshot", "")
+	if recorder.Code != http.StatusOK || recorder.Header().Get("Content-Type") != "application/gzip" {
+		t.Fatalf("got status %d and content type %q", recorder.Code, recorder.Header().Get("Content-Type"))
+	}
+
+	zr, err := gzip.NewReader(recorder.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s snapshot
+	if err := json.NewDecoder(zr).Decode(&s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Format != SnapshotFormat || s.Generation != 8 || s.Rule != "B36/S23" || len(s.Cells) != 5 {
+		t.Errorf("unexpected snapshot %+v", s)
+	}
+
+	if recorder := request(t, handler, "GET", "/api/sessions/2/snapshot", ""); recorder.Code != http.StatusNotFound {
+		t.Errorf("got status %d for an unknown session, want %d", recorder.Code, http.StatusNotFound)
+	}
+}
+
+func TestSessionDelete(t *testing.T) {
+	handler := NewSessionManager(testParameters()).Handler()
+	request(t, handler, "POST", "/api/sessions", gliderRequest)
+
+	if recorder := request(t, handler, "DELETE", "/api/sessions/1", ""); recorder.Code != http.StatusNoContent {
+		t.Errorf("got status %d, want %d", recorder.Code, http.StatusNoContent)
+	}
+	if recorder := request(t, handler, "GET", "/api/sessions/1", ""); recorder.Code != http.StatusNotFound {
+		t.Errorf("got status %d for a deleted session, want %d", recorder.Code, http.StatusNotFound)
+	}
+	if recorder := request(t, handler, "DELETE", "/api/sessions/1", ""); recorder.Code != http.StatusNotFound {
+		t.Errorf("got status %d deleting twice, want %d", recorder.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,151 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	// SpaceshipCheckEvery is the number of generations between two spaceship detections.
+	SpaceshipCheckEvery = 50
+	// trackingMaxCells and trackingMaxPeriod keep the detection cheap,
+	// larger or slower objects are considered a part of the core.
+	trackingMaxCells  = 64
+	trackingMaxPeriod = 16
+)
+
+type spaceship struct {
+	cells  []Coord
+	bounds Bounds
+	entry  CensusEntry
+}
+
+// TrackSpaceships finds the spaceships of the universe and summarises them for the info text.
+// With escapeDistance above 0 the spaceships farther than the distance from the rest
+// of the infinite universe and moving away from it are removed.
+func (game *Game) TrackSpaceships(escapeDistance int) {
+	u := game.Universe
+	parameters := u.Parameters()
+
+	var ships []spaceship
+	core := emptyBounds()
+	for _, object := range separateObjects(u) {
+		bounds := coordBounds(object)
+		if len(object) <= trackingMaxCells {
+			entry := classifyObject(object, parameters, trackingMaxPeriod)
+			if entry.Class == ClassSpaceship {
+				ships = append(ships, spaceship{object, bounds, entry})
+				continue
+			}
+		}
+		core = core.union(bounds)
+	}
+
+	if escapeDistance > 0 && *parameters.boardType == "infinite" && !core.isEmpty() {
+		escaped := make(map[Coord]bool)
+		remaining := ships[:0]
+		for _, ship := range ships {
+			distance := boundsDistance(ship.bounds, core)
+			moved := ship.bounds.translate(ship.entry.Dx, ship.entry.Dy)
+			if distance > escapeDistance && boundsDistance(moved, core) > distance {
+				for _, c := range ship.cells {
+					escaped[c] = true
+				}
+				game.escaped++
+				continue
+			}
+			remaining = append(remaining, ship)
+		}
+		ships = remaining
+		removeCells(u, escaped)
+	}
+
+	game.spaceships = summarizeSpaceships(ships)
+}
+
+// summarizeSpaceships counts the spaceships by velocity and period, the most common first.
+func summarizeSpaceships(ships []spaceship) string {
+	type group struct {
+		name  string
+		count int
+	}
+	counts := make(map[string]int)
+	for _, ship := range ships {
+		counts[fmt.Sprintf("%s p%d", ship.entry.Velocity, ship.entry.Period)]++
+	}
+	groups := make([]group, 0, len(counts))
+	for name, count := range counts {
+		groups = append(groups, group{name, count})
+	}
+	slices.SortFunc(groups, func(a, b group) int {
+		return cmp.Or(cmp.Compare(b.count, a.count), cmp.Compare(a.name, b.name))
+	})
+
+	parts := make([]string, len(groups))
+	for i, g := range groups {
+		parts[i] = fmt.Sprintf("%d %s", g.count, g.name)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// removeCells kills the cells keeping the generation and the statistics.
+func removeCells(u Universe, remove map[Coord]bool) {
+	if len(remove) == 0 {
+		return
+	}
+
+	cells := make(map[Coord]int, u.AliveCount())
+	u.ForEachAlive(func(cell Coord, age int) {
+		if !remove[cell] {
+			cells[cell] = age
+		}
+	})
+	stats := u.Stats()
+	genStats := stats[u.Generation()]
+	genStats.alive = len(cells)
+	stats[u.Generation()] = genStats
+	u.Restore(u.Generation(), cells, stats)
+}
+
+func coordBounds(cells []Coord) Bounds {
+	bounds := emptyBounds()
+	for _, c := range cells {
+		bounds = bounds.union(Bounds{c, c})
+	}
+	return bounds
+}
+
+func (b Bounds) translate(dx int, dy int) Bounds {
+	return Bounds{
+		Coord{b.TopLeft.X + dx, b.TopLeft.Y + dy},
+		Coord{b.BottomRight.X + dx, b.BottomRight.Y + dy},
+	}
+}
+
+// boundsDistance is the number of cells between the boxes along the farther axis, 0 when they overlap.
+func boundsDistance(a Bounds, b Bounds) int {
+	return max(0,
+		a.TopLeft.X-b.BottomRight.X, b.TopLeft.X-a.BottomRight.X,
+		a.TopLeft.Y-b.BottomRight.Y, b.TopLeft.Y-a.BottomRight.Y)
+}
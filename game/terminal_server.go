@@ -0,0 +1,379 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/nsf/termbox-go"
+)
+
+const (
+	MaxTerminalZoom         = 16
+	terminalWriteTimeout    = 10 * time.Second
+	maxTerminalSize         = 500
+	maxTelnetSubnegotiation = 64
+)
+
+// Telnet commands and options used to switch the client into character mode
+// and to learn its window size.
+const (
+	telnetSE   = 240
+	telnetSB   = 250
+	telnetWill = 251
+	telnetWont = 252
+	telnetDo   = 253
+	telnetDont = 254
+	telnetIAC  = 255
+
+	telnetEcho              = 1
+	telnetSuppressGoAhead   = 3
+	telnetNegotiateWindow   = 31
+	telnetNegotiateLinemode = 34
+)
+
+// TerminalServer runs a single universe and renders it with ANSI escape sequences
+// to every connected TCP client. Each client has its own origin, zoom and size.
+type TerminalServer struct {
+	mu         sync.Mutex
+	game       *Game
+	parameters *UsageParameters
+	clients    map[*terminalClient]bool
+	frame      Universe
+}
+
+type terminalClient struct {
+	conn     net.Conn
+	origin   Coord
+	zoom     int
+	width    int
+	height   int
+	previous *Screen
+	redraw   chan struct{}
+	quit     chan struct{}
+}
+
+func NewTerminalServer(parameters *UsageParameters) *TerminalServer {
+	game := NewGame(parameters)
+	return &TerminalServer{
+		game:       &game,
+		parameters: parameters,
+		clients:    make(map[*terminalClient]bool),
+	}
+}
+
+// runTerminalServer serves the universe until SIGINT, SIGTERM or SIGHUP is received.
+func runTerminalServer(parameters *UsageParameters) {
+	listener, err := net.Listen("tcp", *parameters.telnet)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s := NewTerminalServer(parameters)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	defer stop()
+	go s.Run(ctx)
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	fmt.Printf("Serving the universe to telnet clients on %s\n", listener.Addr())
+	s.Serve(listener)
+}
+
+// Serve accepts the clients until the listener is closed, then disconnects them.
+func (s *TerminalServer) Serve(listener net.Listener) {
+	var wg sync.WaitGroup
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				log.Print(err)
+			}
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.serveClient(conn)
+		}()
+	}
+
+	s.mu.Lock()
+	for client := range s.clients {
+		client.conn.Close()
+	}
+	s.mu.Unlock()
+	wg.Wait()
+}
+
+// Run advances the universe every sleep interval until the context is done.
+func (s *TerminalServer) Run(ctx context.Context) {
+	tick := time.NewTicker(*s.parameters.sleep)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+		}
+
+		s.mu.Lock()
+		u := s.game.Universe
+		if u.AliveCount() > 0 && !s.parameters.gensReached(u.Generation()) {
+			u.NextStep()
+			s.frame = nil
+			for client := range s.clients {
+				client.requestRedraw()
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+func (s *TerminalServer) serveClient(conn net.Conn) {
+	defer conn.Close()
+
+	client := &terminalClient{
+		conn:   conn,
+		zoom:   1,
+		width:  DefaultScreenWidth,
+		height: DefaultScreenHeight,
+		redraw: make(chan struct{}, 1),
+		quit:   make(chan struct{}),
+	}
+
+	s.mu.Lock()
+	s.clients[client] = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.clients, client)
+		s.mu.Unlock()
+	}()
+
+	_, err := conn.Write([]byte{
+		telnetIAC, telnetWill, telnetEcho,
+		telnetIAC, telnetWill, telnetSuppressGoAhead,
+		telnetIAC, telnetDont, telnetNegotiateLinemode,
+		telnetIAC, telnetDo, telnetNegotiateWindow,
+	})
+	if err != nil {
+		return
+	}
+
+	go s.readInput(client)
+	client.requestRedraw()
+
+	for {
+		select {
+		case <-client.quit:
+			conn.Write([]byte("\x1b[0m\x1b[2J\x1b[H\x1b[?25h"))
+			return
+		case <-client.redraw:
+		}
+
+		if err := s.draw(client); err != nil {
+			return
+		}
+	}
+}
+
+// draw renders the shared universe from the client's origin and sends the difference to the previous frame.
+// The universe is copied once per generation and the copy is rendered by every client outside the lock,
+// so large or zoomed out views do not block the other clients.
+func (s *TerminalServer) draw(client *terminalClient) error {
+	s.mu.Lock()
+	var err error
+	if s.frame == nil {
+		u := s.game.Universe
+		parameters := u.Parameters()
+		stats := map[int]UniverseStats{u.Generation(): u.Stats()[u.Generation()]}
+		s.frame, err = cloneUniverse(u, &parameters, stats)
+	}
+	view := Game{
+		Universe: s.frame,
+		Origin:   client.origin,
+		Metadata: s.game.Metadata,
+		Zoom:     client.zoom,
+	}
+	width, height := client.width, client.height
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	screen := view.Render(width, height)
+
+	frame := screen.ANSI(client.previous, termbox.OutputNormal)
+	if client.previous == nil {
+		frame = "\x1b[?25l" + frame
+	}
+	client.previous = screen
+
+	client.conn.SetWriteDeadline(time.Now().Add(terminalWriteTimeout))
+	_, err = client.conn.Write([]byte(frame))
+	return err
+}
+
+func (c *terminalClient) requestRedraw() {
+	select {
+	case c.redraw <- struct{}{}:
+	default:
+	}
+}
+
+// readInput handles the keys and the telnet negotiation of the client.
+// Arrows pan, + and - zoom, r resets the origin and q disconnects.
+func (s *TerminalServer) readInput(client *terminalClient) {
+	defer close(client.quit)
+
+	reader := bufio.NewReader(client.conn)
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return
+		}
+
+		switch b {
+		case telnetIAC:
+			if !s.readTelnetCommand(client, reader) {
+				return
+			}
+			continue
+		case 0x1b:
+			if !s.readEscapeSequence(client, reader) {
+				return
+			}
+		case 'q', 'Q', 0x03, 0x04:
+			return
+		case '+', '=':
+			s.setZoom(client, client.zoom-1)
+		case '-':
+			s.setZoom(client, client.zoom+1)
+		case 'r', 'R':
+			s.mu.Lock()
+			client.origin = Coord{0, 0}
+			s.mu.Unlock()
+		default:
+			continue
+		}
+		client.requestRedraw()
+	}
+}
+
+func (s *TerminalServer) readEscapeSequence(client *terminalClient, reader *bufio.Reader) bool {
+	prefix, err := reader.ReadByte()
+	if err != nil {
+		return false
+	}
+	if prefix != '[' && prefix != 'O' {
+		return true
+	}
+	key, err := reader.ReadByte()
+	if err != nil {
+		return false
+	}
+
+	step := max(client.zoom, 1)
+	switch key {
+	case 'A':
+		s.pan(client, 0, -step)
+	case 'B':
+		s.pan(client, 0, step)
+	case 'C':
+		s.pan(client, step, 0)
+	case 'D':
+		s.pan(client, -step, 0)
+	}
+	return true
+}
+
+// readTelnetCommand reads the command following IAC, the window size
+// subnegotiation resizes the client's screen.
+func (s *TerminalServer) readTelnetCommand(client *terminalClient, reader *bufio.Reader) bool {
+	command, err := reader.ReadByte()
+	if err != nil {
+		return false
+	}
+
+	switch command {
+	case telnetWill, telnetWont, telnetDo, telnetDont:
+		_, err = reader.ReadByte()
+		return err == nil
+	case telnetSB:
+		var data []byte
+		for {
+			b, err := reader.ReadByte()
+			if err != nil {
+				return false
+			}
+			if b == telnetIAC {
+				next, err := reader.ReadByte()
+				if err != nil {
+					return false
+				}
+				if next == telnetSE {
+					break
+				}
+			}
+			// Only the window size is read, a client sending more is dropped.
+			if len(data) == maxTelnetSubnegotiation {
+				return false
+			}
+			data = append(data, b)
+		}
+		if len(data) == 5 && data[0] == telnetNegotiateWindow {
+			width := int(data[1])<<8 | int(data[2])
+			height := int(data[3])<<8 | int(data[4])
+			if width > 2 && height > 2 {
+				s.mu.Lock()
+				client.width, client.height = min(width, maxTerminalSize), min(height, maxTerminalSize)
+				s.mu.Unlock()
+				client.requestRedraw()
+			}
+		}
+	}
+	return true
+}
+
+func (s *TerminalServer) pan(client *terminalClient, x int, y int) {
+	s.mu.Lock()
+	client.origin.X += x
+	client.origin.Y += y
+	s.mu.Unlock()
+}
+
+func (s *TerminalServer) setZoom(client *terminalClient, zoom int) {
+	s.mu.Lock()
+	client.zoom = min(max(zoom, 1), MaxTerminalZoom)
+	s.mu.Unlock()
+}
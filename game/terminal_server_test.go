@@ -0,0 +1,55 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+)
+
+func TestReadTelnetCommand(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   []byte
+		ok     bool
+		width  int
+		height int
+	}{
+		{"window size", []byte{telnetSB, telnetNegotiateWindow, 0, 80, 0, 24, telnetIAC, telnetSE}, true, 80, 24},
+		{"option", []byte{telnetWill, telnetEcho}, true, 0, 0},
+		{"unterminated", []byte{telnetSB, telnetNegotiateWindow, 0, 80}, false, 0, 0},
+		{"oversized", append(append([]byte{telnetSB}, make([]byte, maxTelnetSubnegotiation+1)...), telnetIAC, telnetSE), false, 0, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := &TerminalServer{}
+			client := &terminalClient{redraw: make(chan struct{}, 1)}
+			ok := s.readTelnetCommand(client, bufio.NewReader(bytes.NewReader(test.data)))
+			if ok != test.ok {
+				t.Errorf("got %v, want %v", ok, test.ok)
+			}
+			if client.width != test.width || client.height != test.height {
+				t.Errorf("got size %dx%d, want %dx%d", client.width, client.height, test.width, test.height)
+			}
+		})
+	}
+}
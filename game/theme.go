@@ -0,0 +1,339 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/nsf/termbox-go"
+)
+
+const DefaultTheme = "dark"
+
+// themeColor is either one of the standard terminal colours or an RGB value
+// which is approximated when the terminal has no truecolor support.
+type themeColor struct {
+	basic termbox.Attribute
+	rgb   [3]uint8
+	isRGB bool
+}
+
+// themeStyle is a foreground colour with its attributes over a background colour.
+type themeStyle struct {
+	fg    themeColor
+	bg    themeColor
+	flags termbox.Attribute
+}
+
+// Theme holds the colours of the terminal UI. Alive cells are coloured by age,
+// the first style is used for the newborn cells and the last one for all the older.
+// The colour of Dead is the background of the board.
+type Theme struct {
+	Name   string
+	Alive  []themeStyle
+	Dead   themeStyle
+	Border themeStyle
+	Text   themeStyle
+	Status themeStyle
+	Cursor themeStyle
+}
+
+var colorNames = []string{
+	"default", "black", "red", "green", "yellow", "blue", "magenta", "cyan", "white",
+	"darkgray", "lightred", "lightgreen", "lightyellow", "lightblue", "lightmagenta", "lightcyan", "lightgray",
+}
+
+var styleFlags = map[string]termbox.Attribute{
+	"bold":      termbox.AttrBold,
+	"dim":       termbox.AttrDim,
+	"italic":    termbox.AttrCursive,
+	"underline": termbox.AttrUnderline,
+	"blink":     termbox.AttrBlink,
+	"reverse":   termbox.AttrReverse,
+}
+
+// themeSources are the built-in themes in the theme file format.
+var themeSources = map[string]string{
+	"dark": `
+alive = green, darkgray
+dead = default
+border = default
+text = default
+status = default bold
+cursor = black on green
+`,
+	"light": `
+alive = #008700, #5f5f5f
+dead = default
+border = #5f5f5f
+text = #000000
+status = #000087 bold
+cursor = #ffffff on #008700
+`,
+	"high-contrast": `
+alive = #ffffff bold, #ffff00
+dead = #000000
+border = #ffff00
+text = #ffffff bold
+status = #ffff00 bold
+cursor = #000000 on #ffff00
+`,
+	"monochrome": `
+alive = default bold, default dim
+dead = default
+border = default
+text = default
+status = default bold
+cursor = default reverse
+`,
+}
+
+// themeNames returns the names of the built-in themes.
+func themeNames() []string {
+	return slices.Sorted(maps.Keys(themeSources))
+}
+
+// loadTheme returns the built-in theme of the name or reads the theme file.
+func loadTheme(name string) (*Theme, error) {
+	if source, ok := themeSources[name]; ok {
+		return parseTheme(name, strings.NewReader(source), nil)
+	}
+
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown theme %q, use one of %s or a theme file", name, strings.Join(themeNames(), ", "))
+	}
+	defer file.Close()
+
+	return parseTheme(name, file, themeFileBase)
+}
+
+// themeFileBase resolves the "base" key of theme files, the keys a theme
+// file doesn't set are taken from its base theme, dark by default.
+func themeFileBase(name string) (*Theme, error) {
+	source, ok := themeSources[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown base theme %q", name)
+	}
+	return parseTheme(name, strings.NewReader(source), nil)
+}
+
+// parseTheme reads the "key = value" lines of a theme, lines starting with '#' or ';' are comments,
+// base must come before the keys it is overridden with.
+//
+//	base = light
+//	alive = #00ff00 bold, #00af00, #5f5f5f
+//	dead = #000000
+//	cursor = black on green
+//
+// Colours are default, the standard colour names or #rrggbb, followed by
+// bold, dim, italic, underline, blink or reverse and optionally "on" a background colour.
+func parseTheme(name string, r io.Reader, base func(string) (*Theme, error)) (*Theme, error) {
+	theme := &Theme{}
+	if base != nil {
+		var err error
+		if theme, err = base(DefaultTheme); err != nil {
+			return nil, err
+		}
+	}
+	theme.Name = name
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || text[0] == '#' || text[0] == ';' {
+			continue
+		}
+		key, value, ok := strings.Cut(text, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected key = value", name, line)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		if err := theme.set(key, value, base); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", name, line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(theme.Alive) == 0 {
+		return nil, fmt.Errorf("%s: no alive colours", name)
+	}
+	return theme, nil
+}
+
+func (t *Theme) set(key string, value string, base func(string) (*Theme, error)) error {
+	if key == "base" {
+		if base == nil {
+			return fmt.Errorf("base is not allowed here")
+		}
+		baseTheme, err := base(value)
+		if err != nil {
+			return err
+		}
+		name := t.Name
+		*t = *baseTheme
+		t.Name = name
+		return nil
+	}
+
+	if key == "alive" {
+		var alive []themeStyle
+		for _, spec := range strings.Split(value, ",") {
+			style, err := parseThemeStyle(spec)
+			if err != nil {
+				return err
+			}
+			alive = append(alive, style)
+		}
+		t.Alive = alive
+		return nil
+	}
+
+	styles := map[string]*themeStyle{
+		"dead":   &t.Dead,
+		"border": &t.Border,
+		"text":   &t.Text,
+		"status": &t.Status,
+		"cursor": &t.Cursor,
+	}
+	style, ok := styles[key]
+	if !ok {
+		return fmt.Errorf("unknown key %q, expected base, alive, dead, border, text, status or cursor", key)
+	}
+	parsed, err := parseThemeStyle(value)
+	if err != nil {
+		return err
+	}
+	*style = parsed
+	return nil
+}
+
+// parseThemeStyle parses "colour [attributes...] [on colour]".
+func parseThemeStyle(spec string) (themeStyle, error) {
+	var style themeStyle
+
+	fields := strings.Fields(strings.ToLower(spec))
+	if len(fields) == 0 {
+		return style, fmt.Errorf("missing colour")
+	}
+	if i := slices.Index(fields, "on"); i >= 0 {
+		if i != len(fields)-2 {
+			return style, fmt.Errorf("invalid style %q, expected one background colour after on", spec)
+		}
+		bg, err := parseThemeColor(fields[i+1])
+		if err != nil {
+			return style, err
+		}
+		style.bg = bg
+		fields = fields[:i]
+	}
+
+	fg, err := parseThemeColor(fields[0])
+	if err != nil {
+		return style, err
+	}
+	style.fg = fg
+	for _, field := range fields[1:] {
+		flag, ok := styleFlags[field]
+		if !ok {
+			return style, fmt.Errorf("unknown attribute %q", field)
+		}
+		style.flags |= flag
+	}
+	return style, nil
+}
+
+func parseThemeColor(spec string) (themeColor, error) {
+	if i := slices.Index(colorNames, spec); i >= 0 {
+		return themeColor{basic: termbox.Attribute(i)}, nil
+	}
+	if !strings.HasPrefix(spec, "#") {
+		return themeColor{}, fmt.Errorf("invalid colour %q, expected #rrggbb or one of %s", spec, strings.Join(colorNames, ", "))
+	}
+	c, err := parseHexColor(spec)
+	if err != nil {
+		return themeColor{}, err
+	}
+	return themeColor{rgb: [3]uint8{c.R, c.G, c.B}, isRGB: true}, nil
+}
+
+// attribute returns the termbox colour for the output mode, RGB values are
+// approximated by the 256 colour palette or by the nearest standard colour.
+func (c themeColor) attribute(mode termbox.OutputMode) termbox.Attribute {
+	if !c.isRGB {
+		return c.basic
+	}
+
+	switch mode {
+	case termbox.OutputRGB:
+		return termbox.RGBToAttribute(c.rgb[0], c.rgb[1], c.rgb[2])
+	case termbox.Output256:
+		return colorCubeAttribute(c.rgb)
+	default:
+		nearest, distance := 0, -1
+		for i, ansi := range ansiColors {
+			d := 0
+			for j := range ansi {
+				d += (int(ansi[j]) - int(c.rgb[j])) * (int(ansi[j]) - int(c.rgb[j]))
+			}
+			if distance < 0 || d < distance {
+				nearest, distance = i, d
+			}
+		}
+		return termbox.ColorBlack + termbox.Attribute(nearest)
+	}
+}
+
+// colors returns the foreground with the attributes and the background of the style.
+func (s themeStyle) colors(mode termbox.OutputMode) (termbox.Attribute, termbox.Attribute) {
+	return s.fg.attribute(mode) | s.flags, s.bg.attribute(mode)
+}
+
+// aliveStyle returns the style of the cells of the age.
+func (t *Theme) aliveStyle(age int) themeStyle {
+	return t.Alive[min(max(age, 1), len(t.Alive))-1]
+}
+
+var defaultTheme = mustLoadTheme(DefaultTheme)
+
+func mustLoadTheme(name string) *Theme {
+	theme, err := loadTheme(name)
+	if err != nil {
+		panic(err)
+	}
+	return theme
+}
+
+// colorTheme returns the theme of the parameters, the default one when none was set.
+func (p UsageParameters) colorTheme() *Theme {
+	if p.theme != nil {
+		return p.theme
+	}
+	return defaultTheme
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright (c) 2025 Borys Nebosenko
+ *
+ * This file is part of Go-life.
+ *
+ * Go-life is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published
+ * by the Free Software Foundation, either version 3 of the License,
+ * or (at your option) any later version.
+ *
+ * Go-life is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Go-life.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package game
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// savePattern writes the pattern in the given format,
+// when the format is empty it is chosen by the file extension.
+func savePattern(target string, format string, pattern Pattern) error {
+
+	if format == "" {
+		format = formatOf(target)
+	}
+
+	file, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+
+	err = writePattern(file, format, pattern)
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func writePattern(w io.Writer, format string, pattern Pattern) error {
+	switch format {
+	case FormatRLE:
+		return writeRLE(w, pattern)
+	case FormatLife105:
+		return writeLife105(w, pattern)
+	case FormatLife106:
+		return writeLife106(w, pattern)
+	case FormatMacrocell:
+		return writeMacrocell(w, pattern)
+	default:
+		return writeCells(w, pattern)
+	}
+}
+
+func writeCells(w io.Writer, pattern Pattern) error {
+
+	bw := bufio.NewWriter(w)
+	width, height := matrixSize(pattern.Cells)
+
+	if pattern.Metadata.Name != "" {
+		fmt.Fprintf(bw, "!Name: %s\n", pattern.Metadata.Name)
+	}
+	if pattern.Metadata.Author != "" {
+		fmt.Fprintf(bw, "!Author: %s\n", pattern.Metadata.Author)
+	}
+	for _, comment := range pattern.Metadata.Comments {
+		fmt.Fprintf(bw, "!%s\n", comment)
+	}
+
+	for y := range height {
+		line := make([]byte, width)
+		for x := range width {
+			if pattern.Cells[x][y] {
+				line[x] = 'O'
+			} else {
+				line[x] = '.'
+			}
+		}
+		fmt.Fprintln(bw, strings.TrimRight(string(line), "."))
+	}
+
+	return bw.Flush()
+}